@@ -105,6 +105,9 @@ func testNewCommandBuilder(t *testing.T) {
 	bb.Examples(`$APP --verbose|$APP -v`)
 	bb.Group("zzz9.Misc")
 	bb.Deprecated("v2.0")
+	bb.Notes("a note")
+	bb.SeeAlso("help-system.commands")
+	bb.Since("v1.4")
 	bb.Hidden(true, true)
 	bb.TailPlaceHolders("")
 	bb.RedirectTo("help-system.commands")
@@ -112,6 +115,7 @@ func testNewCommandBuilder(t *testing.T) {
 	bb.OnAction(nil)
 	bb.OnPreAction(nil)
 	bb.OnPostAction(nil)
+	bb.Use(cli.RecoverMiddleware())
 	bb.PresetCmdLines("")
 	bb.InvokeProc("")
 	bb.InvokeShell("")
@@ -158,6 +162,9 @@ func testNewFlagBuilder(t *testing.T) {
 	bb.Examples(`$APP --verbose|$APP -v`)
 	bb.Group("zzz9.Misc")
 	bb.Deprecated("v2.0")
+	bb.Notes("a note")
+	bb.SeeAlso("help-system.commands")
+	bb.Since("v1.4")
 	bb.Hidden(true, true)
 	bb.ToggleGroup("zzz9.Misc")
 	bb.PlaceHolder("")
@@ -5,6 +5,7 @@ package builder
 import (
 	"fmt"
 	"sync/atomic"
+	"time"
 
 	"github.com/hedzr/cmdr/v2/cli"
 	logz "github.com/hedzr/logg/slog"
@@ -236,6 +237,21 @@ func (s *ccb) Deprecated(deprecated string) cli.CommandBuilder {
 	return s
 }
 
+func (s *ccb) Notes(notes ...string) cli.CommandBuilder {
+	s.SetNotes(notes...)
+	return s
+}
+
+func (s *ccb) SeeAlso(dottedPaths ...string) cli.CommandBuilder {
+	s.SetSeeAlso(dottedPaths...)
+	return s
+}
+
+func (s *ccb) Since(version string) cli.CommandBuilder {
+	s.SetSince(version)
+	return s
+}
+
 func (s *ccb) Hidden(hidden bool, vendorHidden ...bool) cli.CommandBuilder {
 	s.SetHidden(hidden, vendorHidden...)
 	return s
@@ -274,6 +290,21 @@ func (s *ccb) OnPostAction(handlers ...cli.OnPostInvokeHandler) cli.CommandBuild
 	return s
 }
 
+func (s *ccb) Use(mws ...cli.Middleware) cli.CommandBuilder {
+	s.CmdS.Use(mws...)
+	return s
+}
+
+func (s *ccb) Timeout(d time.Duration) cli.CommandBuilder {
+	s.SetTimeout(d)
+	return s
+}
+
+func (s *ccb) Confirm(question string) cli.CommandBuilder {
+	s.SetConfirm(question)
+	return s
+}
+
 func (s *ccb) OnMatched(handler cli.OnCommandMatchedHandler) cli.CommandBuilder {
 	s.SetOnMatched(handler)
 	return s
@@ -115,6 +115,21 @@ func (s *ffb) Deprecated(deprecated string) cli.FlagBuilder {
 	return s
 }
 
+func (s *ffb) Notes(notes ...string) cli.FlagBuilder {
+	s.Flag.SetNotes(notes...)
+	return s
+}
+
+func (s *ffb) SeeAlso(dottedPaths ...string) cli.FlagBuilder {
+	s.Flag.SetSeeAlso(dottedPaths...)
+	return s
+}
+
+func (s *ffb) Since(version string) cli.FlagBuilder {
+	s.Flag.SetSince(version)
+	return s
+}
+
 func (s *ffb) Hidden(hidden bool, vendorHidden ...bool) cli.FlagBuilder {
 	s.Flag.SetHidden(hidden, vendorHidden...)
 	return s
@@ -175,6 +190,15 @@ func (s *ffb) Required(required bool) cli.FlagBuilder {
 	return s
 }
 
+func (s *ffb) Secret(b ...bool) cli.FlagBuilder {
+	v := true
+	for _, bv := range b {
+		v = v && bv
+	}
+	s.Flag.SetSecret(v)
+	return s
+}
+
 func (s *ffb) BindVarPtr(varptr any) cli.FlagBuilder {
 	rv := reflect.ValueOf(varptr)
 	if ref.IsPtr(rv) {
@@ -335,6 +335,10 @@ func (s *sbS) constructFrom(ctx constructCtx) (err error) {
 			fb.Required(required)
 		}
 
+		if secret := is.StringToBool(tag.Get("secret")); secret {
+			fb.Secret(secret)
+		}
+
 		envvars := strings.Split(nonEmpty(tag.Get("env"), tag.Get("envvars")), ",")
 		if len(envvars) > 0 {
 			fb.EnvVars(envvars...)
@@ -31,6 +31,9 @@ func (c *BaseOpt) Clone() any {
 		deprecated:   c.deprecated,
 		hidden:       c.hidden,
 		vendorHidden: c.vendorHidden,
+		notes:        slices.Clone(c.notes),
+		seeAlso:      slices.Clone(c.seeAlso),
+		since:        c.since,
 		hitTitle:     c.hitTitle,
 		hitTimes:     c.hitTimes,
 	}
@@ -110,6 +113,21 @@ func (c *BaseOpt) SetDeprecated(deprecated string) {
 	c.deprecated = deprecated
 }
 
+// SetNotes appends the extra notes.
+func (c *BaseOpt) SetNotes(notes ...string) {
+	c.notes = append(c.notes, notes...)
+}
+
+// SetSeeAlso appends the dotted paths of the related commands.
+func (c *BaseOpt) SetSeeAlso(dottedPaths ...string) {
+	c.seeAlso = append(c.seeAlso, dottedPaths...)
+}
+
+// SetSince sets the version which this command/flag was added in.
+func (c *BaseOpt) SetSince(version string) {
+	c.since = version
+}
+
 func (c *BaseOpt) SetHidden(hidden bool, vendorHidden ...bool) {
 	c.hidden = hidden
 	for _, b := range vendorHidden {
@@ -334,6 +352,9 @@ func (c *BaseOpt) SetDesc(desc string) {
 
 func (c *BaseOpt) Examples() string   { return c.examples }
 func (c *BaseOpt) Deprecated() string { return c.deprecated }
+func (c *BaseOpt) Notes() []string    { return c.notes }
+func (c *BaseOpt) SeeAlso() []string  { return c.seeAlso }
+func (c *BaseOpt) Since() string      { return c.since }
 func (c *BaseOpt) Hidden() bool       { return c.hidden }
 func (c *BaseOpt) VendorHidden() bool { return c.vendorHidden }
 
@@ -409,7 +430,8 @@ func (c *BaseOpt) GroupHelpTitle() string {
 	if tmp == UnsortedGroup {
 		return ""
 	}
-	return c.RemoveOrderedPrefix(tmp)
+	title := c.RemoveOrderedPrefix(tmp)
+	return localized("group."+title, title)
 }
 
 // GetTitleNamesArray returns short,full,aliases names
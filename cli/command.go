@@ -8,6 +8,7 @@ import (
 	"slices"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/hedzr/cmdr/v2/internal/tool"
 	"github.com/hedzr/cmdr/v2/pkg/logz"
@@ -38,6 +39,9 @@ func (c *CmdS) Clone() any {
 		preActions:  slices.Clone(c.preActions),
 		onInvoke:    c.onInvoke,
 		postActions: slices.Clone(c.postActions),
+		middlewares: slices.Clone(c.middlewares),
+		timeout:     c.timeout,
+		confirm:     c.confirm,
 
 		onMatched: slices.Clone(c.onMatched),
 
@@ -103,6 +107,27 @@ func (c *CmdS) String() string {
 	return sb.String()
 }
 
+// Desc returns the description, translated by the message
+// catalog with ID 'cmd.<dotted.path>.desc', see RegisterMessages.
+func (c *CmdS) Desc() string {
+	return localizedText("cmd", &c.BaseOpt, "desc", c.BaseOpt.Desc())
+}
+
+// DescLong returns the long description, translated by the
+// message catalog with ID 'cmd.<dotted.path>.long'.
+func (c *CmdS) DescLong() string {
+	if c.longDesc == "" {
+		return c.Desc()
+	}
+	return localizedText("cmd", &c.BaseOpt, "long", c.longDesc)
+}
+
+// Examples returns the examples, translated by the message
+// catalog with ID 'cmd.<dotted.path>.examples'.
+func (c *CmdS) Examples() string {
+	return localizedText("cmd", &c.BaseOpt, "examples", c.examples)
+}
+
 // TailPlaceHolder is a string at end of usage line in help screen.
 //
 // In help screen, a command's usage line generally has the following form:
@@ -453,6 +478,40 @@ func (c *CmdS) SetPreActions(functions ...OnPreInvokeHandler) {
 	c.preActions = append(c.preActions, functions...)
 }
 
+// Use adds the middlewares which wrap the action of this command
+// and its subcommands.
+func (c *CmdS) Use(mws ...Middleware) {
+	c.middlewares = append(c.middlewares, mws...)
+}
+
+// Middlewares returns the middlewares added by Use.
+func (c *CmdS) Middlewares() []Middleware { return c.middlewares }
+
+// SetTimeout gives the action of this command a deadline. The
+// context passed to the action is canceled after d, and the
+// alias commands (InvokeProc and InvokeShell) are killed.
+//
+// A zero d means no limit. The builtin flag `--timeout`
+// overrides it.
+func (c *CmdS) SetTimeout(d time.Duration) { c.timeout = d }
+
+// Timeout returns the deadline set by SetTimeout.
+func (c *CmdS) Timeout() time.Duration { return c.timeout }
+
+// SetConfirm requires the user to confirm before invoking the
+// action of this command, which is destructive. The question may
+// have a "%d" for the count of positional args, such as "This
+// will delete %d resources". It's the only verb, "%%" is a
+// literal '%' and any other '%' is kept as is.
+//
+// The builtin flag `--yes` answers it, and it's an error without
+// `--yes` if the stdin is not a terminal. It's skipped in
+// `--dry-run` mode.
+func (c *CmdS) SetConfirm(question string) { c.confirm = question }
+
+// Confirm returns the question set by SetConfirm.
+func (c *CmdS) Confirm() string { return c.confirm }
+
 // SetAction adds the onInvoke action to a command.
 //
 // a call to `SetAction(nil)` will set the underlying onAction handlet empty.
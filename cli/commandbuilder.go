@@ -1,5 +1,7 @@
 package cli
 
+import "time"
+
 type OptBuilder interface {
 	// Build connects the built command into the building command system.
 	Build()
@@ -61,6 +63,18 @@ type CommandBuilder interface {
 	// Deprecated is a version string just like '0.5.9' or 'v0.5.9', that
 	// means this command/flag was/will be deprecated since `v0.5.9`.
 	Deprecated(deprecated string) CommandBuilder
+	// Notes appends the extra notes, which will be shown in the
+	// Notes section of help screen, manpage and docs.
+	Notes(notes ...string) CommandBuilder
+	// SeeAlso appends the related commands by dotted path, such
+	// as "server.start". They will be shown in the See Also section.
+	SeeAlso(dottedPaths ...string) CommandBuilder
+	// Since is a version string just like 'v1.4', that means this
+	// command was added in `v1.4`.
+	//
+	// The command will be hidden if the builtin `--version-sim`
+	// specifies an older version.
+	Since(version string) CommandBuilder
 	// Hidden command/flag won't be shown in help-screen and others output.
 	//
 	// The Hidden command/flag may be printed normally if very verbose mode
@@ -128,6 +142,20 @@ type CommandBuilder interface {
 	OnPreAction(handlers ...OnPreInvokeHandler) CommandBuilder
 	// OnPostAction will be launched after running OnInvoke.
 	OnPostAction(handlers ...OnPostInvokeHandler) CommandBuilder
+	// Use adds the middlewares which wrap the action of this
+	// command and its subcommands, such as timing, tracing and
+	// auth checks. See also [Middleware].
+	Use(mws ...Middleware) CommandBuilder
+	// Timeout gives the action a deadline, the action gets a
+	// TimeoutError if it doesn't return in time. The builtin
+	// flag `--timeout` overrides it.
+	Timeout(d time.Duration) CommandBuilder
+	// Confirm requires the user to confirm the question before
+	// invoking the action, such as "This will delete %d
+	// resources" (the count of positional args, "%d" is the only
+	// verb, see CmdS.SetConfirm). The builtin flag `--yes` answers
+	// it.
+	Confirm(question string) CommandBuilder
 
 	// OnMatched _.
 	OnMatched(handler OnCommandMatchedHandler) CommandBuilder
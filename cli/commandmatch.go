@@ -463,7 +463,7 @@ func (c *CmdS) matchedForTG(ctx context.Context, ff *Flag) *Flag {
 func (c *CmdS) checkJustOnce(vp *FlagValuePkg, ff *Flag) (ret *Flag, err error) {
 	if ff != nil && ff.justOnce {
 		if ff.hitTimes > 1 {
-			err = ErrFlagJustOnce.FormatWith(ff)
+			err = LocalizeError(MsgErrFlagJustOnce, ErrFlagJustOnce, ff)
 			return
 		}
 	}
@@ -484,7 +484,7 @@ func (c *CmdS) checkPrerequisites(ctx context.Context, vp *FlagValuePkg, ff *Fla
 				}
 				if f != nil {
 					if f.hitTimes < 0 {
-						err = ErrMissedPrerequisite.FormatWith(ff, f)
+						err = LocalizeError(MsgErrMissedPrereq, ErrMissedPrerequisite, ff, f)
 						return
 					}
 				}
@@ -513,15 +513,58 @@ func (c *CmdS) tryParseStringValue(ctx context.Context, vp *FlagValuePkg, ff *Fl
 
 	if vp.Remains != "" {
 		vp.ValueOK, vp.Value, vp.Remains = true, c.normalizeStringValue(vp.Remains), ""
-	} else if vp.AteArgs < len(vp.Args) {
+	} else if vp.AteArgs < len(vp.Args) && !(ff.secret && c.isKnownFlag(ctx, vp.Args[vp.AteArgs])) {
 		vp.ValueOK, vp.Value, vp.AteArgs = true, c.normalizeStringValue(vp.Args[vp.AteArgs]), vp.AteArgs+1
 	} else {
+		// value missed. For a secret flag, it will be prompted later.
 		vp.ValueOK, vp.Value = true, ""
 	}
 	ff.defaultValue = vp.Value
 	return ff
 }
 
+// isKnownFlag tests if arg is the end-of-flags marker `--` or a
+// flag of c or its parents, such as `--verbose`, `-v` and
+// `~~debug`. A secret value may start with '-' or '~' too, so the
+// unknown ones are not treated as flags.
+func (c *CmdS) isKnownFlag(ctx context.Context, arg string) bool {
+	if arg == "--" {
+		return true
+	}
+	var title string
+	var short bool
+	switch {
+	case strings.HasPrefix(arg, "--"), strings.HasPrefix(arg, "~~"):
+		title = arg[2:]
+	case strings.HasPrefix(arg, "-"), strings.HasPrefix(arg, "~"):
+		title, short = arg[1:], true
+	default:
+		return false
+	}
+	if pos := strings.IndexRune(title, '='); pos >= 0 {
+		title = title[:pos]
+	}
+	if title == "" {
+		return false
+	}
+
+	for cc := c; cc != nil; {
+		cc.ensureXrefFlags(ctx)
+		flags := cc.longFlags
+		if short {
+			flags = cc.shortFlags
+		}
+		if _, ok := flags[title]; ok {
+			return true
+		}
+		if !cc.OwnerIsValid() {
+			break
+		}
+		cc, _ = cc.owner.(*CmdS)
+	}
+	return false
+}
+
 func (c *CmdS) tryParseBoolValue(ctx context.Context, vp *FlagValuePkg, ff *Flag) *Flag {
 	if len(vp.Remains) > 0 {
 		switch ch := vp.Remains[0]; ch {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"strings"
+	"time"
 
 	"gopkg.in/hedzr/errors.v3"
 
@@ -14,6 +15,7 @@ const (
 	DefaultStoreKeyPrefix = "app"
 	CommandsStoreKey      = "cmd"
 	PeripheralsStoreKey   = "peripherals"
+	ProfilesStoreKey      = "profiles"
 )
 
 func NewConfig(opts ...Opt) *Config {
@@ -33,25 +35,52 @@ func DefaultConfig() *Config {
 type Config struct {
 	store.Store `json:"store,omitempty"` // default is a dummy store. create yours with store.New().
 
-	ForceDefaultAction    bool              `json:"force_default_action,omitempty"`    // use builtin action for debugging if no Action specified to a command
-	DontGroupInHelpScreen bool              `json:"no_group_in_help_screen,omitempty"` // group commands and flags by its group-name
-	DontExecuteAction     bool              `json:"no_execute_action,omitempty"`       // just parsing, without executing [cli.Cmd.OnAction]
-	SortInHelpScreen      bool              `json:"sort_in_help_screen,omitempty"`     // auto sort commands and flags rather than creating order
-	UnmatchedAsError      bool              `json:"unmatched_as_error,omitempty"`      // unmatched command or flag as an error and threw it
-	TasksAfterXref        []Task            `json:"-"`                                 // while command linked and xref'd, it's time to insert user-defined commands dynamically.
-	TasksAfterLoader      []Task            `json:"-"`                                 // while external loaders loaded.
-	TasksBeforeParse      []Task            `json:"-"`                                 // globally pre-parse tasks
-	TasksParsed           []Task            `json:"-"`                                 // globally post-parse tasks
-	TasksBeforeRun        []Task            `json:"-"`                                 // globally pre-run tasks, it's also used as TasksAfterParsed
-	TasksAfterRun         []Task            `json:"-"`                                 // globally post-run tasks
-	TasksPostCleanup      []Task            `json:"-"`                                 // globally post-run tasks, specially for cleanup actions
-	Loaders               []Loader          `json:"-"`                                 // external loaders. use cli.WithLoader() prefer
-	HelpScreenWriter      HelpWriter        `json:"help_screen_writer,omitempty"`      // redirect stdout for help screen printing
-	DebugScreenWriter     HelpWriter        `json:"debug_screen_writer,omitempty"`     // redirect stdout for debugging outputs
-	Args                  []string          `json:"args,omitempty"`                    // for testing
-	Env                   map[string]string `json:"env,omitempty"`                     // inject env var & values
-	AutoEnv               bool              `json:"auto_env,omitempty"`                // enable envvars auto-binding?
-	AutoEnvPrefix         string            `json:"auto_env_prefix,omitempty"`         // envvars auto-binding prefix, bind them to corresponding flags
+	ForceDefaultAction    bool                      `json:"force_default_action,omitempty"`    // use builtin action for debugging if no Action specified to a command
+	DontGroupInHelpScreen bool                      `json:"no_group_in_help_screen,omitempty"` // group commands and flags by its group-name
+	DontExecuteAction     bool                      `json:"no_execute_action,omitempty"`       // just parsing, without executing [cli.Cmd.OnAction]
+	SortInHelpScreen      bool                      `json:"sort_in_help_screen,omitempty"`     // auto sort commands and flags rather than creating order
+	UnmatchedAsError      bool                      `json:"unmatched_as_error,omitempty"`      // unmatched command or flag as an error and threw it
+	TasksAfterXref        []Task                    `json:"-"`                                 // while command linked and xref'd, it's time to insert user-defined commands dynamically.
+	TasksAfterLoader      []Task                    `json:"-"`                                 // while external loaders loaded.
+	TasksBeforeParse      []Task                    `json:"-"`                                 // globally pre-parse tasks
+	TasksParsed           []Task                    `json:"-"`                                 // globally post-parse tasks
+	TasksBeforeRun        []Task                    `json:"-"`                                 // globally pre-run tasks, it's also used as TasksAfterParsed
+	TasksAfterRun         []Task                    `json:"-"`                                 // globally post-run tasks
+	TasksPostCleanup      []Task                    `json:"-"`                                 // globally post-run tasks, specially for cleanup actions, always invoked
+	Loaders               []Loader                  `json:"-"`                                 // external loaders. use cli.WithLoader() prefer
+	Middlewares           []Middleware              `json:"-"`                                 // wrap the actions of all commands, the outermost ones
+	SecretResolvers       map[string]SecretResolver `json:"-"`                                 // resolvers for the indirect values of secret flags, such as 'vault:path'
+	HelpScreenWriter      HelpWriter                `json:"help_screen_writer,omitempty"`      // redirect stdout for help screen printing
+	HelpPainter           HelpPainter               `json:"-"`                                 // render the help screen with your own layout
+	Theme                 *Theme                    `json:"-"`                                 // the colors of help and debug screens, see also ThemeDefault, ThemeLight, ...
+	HelpTopics            []*HelpTopic              `json:"-"`                                 // the non-command help pages, such as 'app help config-format'
+	DebugScreenWriter     HelpWriter                `json:"debug_screen_writer,omitempty"`     // redirect stdout for debugging outputs
+	Args                  []string                  `json:"args,omitempty"`                    // for testing
+	Env                   map[string]string         `json:"env,omitempty"`                     // inject env var & values
+	AutoEnv               bool                      `json:"auto_env,omitempty"`                // enable envvars auto-binding?
+	AutoEnvPrefix         string                    `json:"auto_env_prefix,omitempty"`         // envvars auto-binding prefix, bind them to corresponding flags
+	Profile               string                    `json:"profile,omitempty"`                 // the default config profile, overridden by '--profile NAME' or '$APP_PROFILE'
+	LoadDotEnv            bool                      `json:"load_dotenv,omitempty"`             // load '.env', '.env.local' and '.env.<profile>' files before binding envvars
+
+	Locale   string                       `json:"locale,omitempty"` // the language of help screen and messages, such as 'zh-CN', default is detected from '$LC_ALL' or '$LANG' if Messages is not empty
+	Messages map[string]map[string]string `json:"-"`                // the translations by locale and message ID, see RegisterMessages
+
+	HandleSignals bool               `json:"handle_signals,omitempty"` // cancel the Run context on SIGINT/SIGTERM, and route SIGHUP to OnReload
+	GracePeriod   time.Duration      `json:"grace_period,omitempty"`   // how long to wait for the action after the first signal, default is 10s
+	OpenTimeout   time.Duration      `json:"open_timeout,omitempty"`   // the startup timeout of opening or probing ('~~health') each peripheral, zero means no limit
+	CloseTimeout  time.Duration      `json:"close_timeout,omitempty"`  // the timeout of closing each peripheral at exiting, zero means no limit
+	Peripherals   *PeripheralManager `json:"-"`                        // opened in dependency order before running the action and closed in reverse order of opening at exiting, see cmdr.WithPeripherals()
+	OnReload      OnSignalHandler    `json:"-"`                        // invoked on SIGHUP if HandleSignals is set
+
+	PresentErrors bool      `json:"present_errors,omitempty"` // print the error returned by Run to ErrorWriter, see ErrorReport
+	ErrorFormat   string    `json:"error_format,omitempty"`   // the format of the presented error, 'text' (default) or 'json', overridden by '--error-format'
+	ErrorWriter   io.Writer `json:"-"`                        // where the error presenter prints to, default is os.Stderr
+
+	Server *ServerOptions `json:"-"` // enables the builtin command group 'server' for a daemon, see cmdr.WithServer()
+
+	PromptRequired bool `json:"prompt_required,omitempty"` // prompts for the missing required flags on a terminal instead of ErrRequiredFlag, disabled by '--no-input'
+
+	OutputFormat string `json:"output_format,omitempty"` // the default format of cli.Output(ctx), and enables the builtin flags '--output', '--columns' and '--template'
 
 	OnInterpretLeadingPlusSign OnInterpretLeadingPlusSign `json:"-"` // parsing '+shortFlag`
 	OnShowVersion              OnInvokeHandler            `json:"-"`
@@ -97,6 +126,8 @@ type Runner interface {
 	SetSuggestRetCode(ret int)                // update ret code (0-255) from onAction, onTask, ...
 	ParsedState() ParsedState                 // the parsed states
 	LoadedSources() (results []LoadedSources) // the loaded sources
+	Profile() string                          // the active config profile, empty if none
+	Phase() Phase                             // the current lifecycle phase of Run
 
 	// Actions return a state map.
 	// The states can be:
@@ -106,6 +137,7 @@ type Runner interface {
 	//   - show-help-man
 	//   - show-tree
 	//   - show-debug
+	//   - show-env
 	// These states are produced by parsing the builtin flags
 	// with user's command line arguments.
 	// For examples, `~~tree` causes 'show-tree' state ON,
@@ -133,6 +165,7 @@ type ParsedState interface {
 	NoCandidateChildCommands() bool
 	HasCmd(longTitle string, validator func(cc Cmd, state *MatchState) bool) (found bool)
 	HasFlag(longTitle string, validator func(ff *Flag, state *MatchState) bool) (found bool)
+	DryRun() bool // the builtin flag '--dry-run' is set, the action should print instead of changing anything
 
 	// colorful
 
@@ -230,6 +263,8 @@ const (
 	ActionShowDebugRaw                               // with `~~raw`
 	ActionShowDebugValueType                         // with `~~type` (?)
 	ActionShowSBOM                                   // show SBOM screen
+	ActionShowSearch                                 // search commands, flags and help text by `help --search`
+	ActionShowHealth                                 // Health. `~~health` | probe the health of peripherals
 	// actionShortMode
 	// actionDblTildeMode
 
@@ -258,9 +293,18 @@ func (e ActionEnum) String() string {
 	if e&ActionShowDebug != 0 {
 		_, _ = sb.WriteString("- ShowDebug\n")
 	}
+	if e&ActionShowDebugEnv != 0 {
+		_, _ = sb.WriteString("- ShowDebugEnv\n")
+	}
 	if e&ActionShowSBOM != 0 {
 		_, _ = sb.WriteString("- ShowSBOM\n")
 	}
+	if e&ActionShowSearch != 0 {
+		_, _ = sb.WriteString("- ShowSearch\n")
+	}
+	if e&ActionShowHealth != 0 {
+		_, _ = sb.WriteString("- ShowHealth\n")
+	}
 	if e&ActionRunHelpSystem != 0 {
 		_, _ = sb.WriteString("- RunHelpSystem\n")
 	}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	errorsv3 "gopkg.in/hedzr/errors.v3"
 )
@@ -12,6 +14,9 @@ var (
 	ErrEmptyRootCommand = errors.New("the RootCommand hasn't been built")                                                             // obs
 	ErrCommandsNotReady = errors.New("the RootCommand hasn't been built, or InitGlobally() failed. Has builder.App.Build() called? ") // obs
 
+	// The messages of the following errors are translated by the
+	// message catalog while they are thrown, see LocalizeError.
+
 	// ErrUnmatchedCommand means Unmatched command found. It's just a state, not a real error, see [cli.Config.UnmatchedAsError]
 	ErrUnmatchedCommand = errorsv3.New("UNKNOWN CmdS FOUND: %q | cmd=%v")
 	// ErrUnmatchedFlag means Unmatched flag found. It's just a state, not a real error, see [cli.Config.UnmatchedAsError]
@@ -22,4 +27,41 @@ var (
 
 	ErrMissedPrerequisite = errorsv3.New("Flag %q needs %q was set at first") // flag need a prerequisite flag exists.
 	ErrFlagJustOnce       = errorsv3.New("Flag %q MUST BE set once only")     // flag cannot be set more than one time.
+	ErrSecretResolving    = errorsv3.New("Flag %q cannot be resolved: %v")    // the indirect value of a secret flag cannot be resolved.
+
+	// ErrConfirmRequired means the command needs a confirmation
+	// but the stdin is not a terminal, see [CmdS.SetConfirm].
+	ErrConfirmRequired = errorsv3.New("Command %q needs a confirmation, use '--yes' to proceed")
+	// ErrNotConfirmed means the user declined the confirmation.
+	ErrNotConfirmed = errorsv3.New("Command %q was not confirmed")
 )
+
+// ErrTimeout is the target to test a TimeoutError by errors.Is.
+var ErrTimeout = errors.New("timeout")
+
+// ExitCodeTimeout is the exit code of an app if its action timed
+// out, the same as GNU timeout(1).
+const ExitCodeTimeout = 124
+
+// TimeoutError means the action of Cmd doesn't return before the
+// deadline given by [CommandBuilder.Timeout] or the builtin flag
+// `--timeout`.
+//
+// It matches both ErrTimeout and context.DeadlineExceeded in
+// errors.Is.
+type TimeoutError struct {
+	Cmd     Cmd
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	var path string
+	if e.Cmd != nil {
+		path = e.Cmd.GetDottedPath()
+	}
+	return Tf(MsgErrTimeout, path, e.Timeout)
+}
+
+func (e *TimeoutError) Is(target error) bool { return target == ErrTimeout }
+
+func (e *TimeoutError) Unwrap() error { return context.DeadlineExceeded }
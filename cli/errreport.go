@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrorHinter can be implemented by an error to give the hints
+// to fix it, which are printed by the error presenter.
+type ErrorHinter interface {
+	Hints() []string
+}
+
+// ErrorReport is the structured form of the error returned by
+// Run, it's printed by the builtin error presenter, see
+// cmdr.WithErrorPresenter() and the builtin flag
+// `--error-format=text|json`.
+type ErrorReport struct {
+	Message string   `json:"error"`             // the concise message
+	Code    int      `json:"code"`              // the exit code, see ExitCodeOf
+	Kind    string   `json:"kind"`              // the name of code, see ExitCodeName
+	Command string   `json:"command,omitempty"` // the dotted path of the matched command
+	Hints   []string `json:"hints,omitempty"`   // how to fix it
+	Chain   []string `json:"chain,omitempty"`   // all messages in the error tree
+}
+
+// NewErrorReport builds the report of err, which occurred while
+// running cmd (can be nil).
+func NewErrorReport(err error, cmd Cmd) (r *ErrorReport) {
+	r = &ErrorReport{Code: ExitCodeOf(err)}
+	r.Kind = ExitCodeName(r.Code)
+
+	var app string
+	if cmd != nil {
+		r.Command = cmd.GetDottedPath()
+		if root := cmd.Root(); root != nil {
+			app = root.AppName
+		}
+	}
+
+	walkErrors(err, 0, func(e error, depth int, leaf bool) {
+		msg := e.Error()
+		if msg == "" {
+			return
+		}
+		if r.Message == "" && leaf {
+			r.Message = msg
+		}
+		r.Chain = append(r.Chain, strings.Repeat("  ", depth)+msg)
+		if h, ok := e.(ErrorHinter); ok {
+			r.Hints = append(r.Hints, h.Hints()...)
+		}
+	})
+	if r.Message == "" && err != nil {
+		r.Message = err.Error()
+	}
+
+	switch r.Code {
+	case ExitUsage:
+		r.Hints = append(r.Hints, Tf(MsgHintUsage, strings.TrimSpace(app+" "+strings.ReplaceAll(r.Command, ".", " "))))
+	case ExitCodeTimeout:
+		r.Hints = append(r.Hints, T(MsgHintTimeout))
+	}
+	return
+}
+
+// walkErrors visits the error tree depth-first. The containers
+// of several errors, such as errors.Join and the errors.v3
+// container, are transparent. An error is a leaf if it isn't
+// wrapped by another one, the errors wrapped by it are still
+// visited.
+func walkErrors(err error, depth int, fn func(e error, depth int, leaf bool)) {
+	if err == nil || depth > 32 {
+		return
+	}
+	if children := childrenOf(err); len(children) > 0 {
+		for _, c := range children {
+			walkErrors(c, depth, fn)
+		}
+		return
+	}
+
+	fn(err, depth, true)
+	if _, ok := err.(interface{ Causes() []error }); ok {
+		return // Unwrap of errors.v3 is stateful
+	}
+	for prev, inner := err, errors.Unwrap(err); inner != nil && depth < 32; prev, inner = inner, errors.Unwrap(inner) {
+		depth++
+		if inner.Error() != prev.Error() {
+			fn(inner, depth, false)
+		}
+	}
+}
+
+// childrenOf returns the errors in a container, such as
+// errors.Join and the errors.v3 container.
+func childrenOf(err error) []error {
+	switch e := err.(type) {
+	case interface{ Causes() []error }: // errors.v3 container
+		return e.Causes()
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	}
+	return nil
+}
+
+// WriteText prints the concise message and the hints, or the
+// full chain of errors if verbose is set.
+func (r *ErrorReport) WriteText(wr io.Writer, verbose bool) (err error) {
+	var sb strings.Builder
+	_, _ = sb.WriteString(T(MsgErrorLabel) + ": ")
+	if verbose && len(r.Chain) > 0 {
+		_, _ = sb.WriteString("\n")
+		for _, ln := range r.Chain {
+			_, _ = sb.WriteString("  " + ln + "\n")
+		}
+	} else {
+		_, _ = sb.WriteString(r.Message + "\n")
+	}
+	for _, h := range r.Hints {
+		_, _ = sb.WriteString("  " + T(MsgHintLabel) + ": " + h + "\n")
+	}
+	_, err = io.WriteString(wr, sb.String())
+	return
+}
+
+// WriteJSON prints the report as a JSON object in one line.
+func (r *ErrorReport) WriteJSON(wr io.Writer) (err error) {
+	return json.NewEncoder(wr).Encode(r)
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// The exit codes in BSD sysexits(3) style, used by ExitCodeOf.
+const (
+	ExitOK          = 0  // successful termination
+	ExitFailure     = 1  // the generic failure, for the unknown errors
+	ExitUsage       = 64 // EX_USAGE, the command was used incorrectly
+	ExitDataErr     = 65 // EX_DATAERR, the input data was incorrect
+	ExitNoInput     = 66 // EX_NOINPUT, an input file did not exist or was not readable
+	ExitUnavailable = 69 // EX_UNAVAILABLE, a service is unavailable
+	ExitSoftware    = 70 // EX_SOFTWARE, an internal software error
+	ExitIOErr       = 74 // EX_IOERR, an error occurred while doing I/O
+	ExitTempFail    = 75 // EX_TEMPFAIL, a temporary failure, the user is invited to retry
+	ExitNoPerm      = 77 // EX_NOPERM, insufficient permission
+	ExitConfig      = 78 // EX_CONFIG, something was found in an unconfigured or misconfigured state
+)
+
+// ExitCoder can be implemented by an error to give its exit code
+// directly, it takes precedence over the registry.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+type exitCodeEntry struct {
+	match func(err error) bool
+	code  int
+}
+
+var exitCodes = struct {
+	sync.RWMutex
+	entries []exitCodeEntry // registered by app
+}{}
+
+// builtinExitCodes maps the errors of cmdr.
+var builtinExitCodes = []exitCodeEntry{
+	{isErr(ErrUnmatchedCommand), ExitUsage},
+	{isErr(ErrUnmatchedFlag), ExitUsage},
+	{isErr(ErrRequiredFlag), ExitUsage},
+	{isErr(ErrValidArgs), ExitUsage},
+	{isErr(ErrMissedPrerequisite), ExitUsage},
+	{isErr(ErrFlagJustOnce), ExitUsage},
+	{isErr(ErrConfirmRequired), ExitUsage},
+	{isErr(ErrSecretResolving), ExitConfig},
+	{isErr(ErrUnhealthy), ExitUnavailable},
+	{isErr(ErrNotRunning), ExitNotRunning},
+	{isErr(ErrTimeout), ExitCodeTimeout},
+	{isErr(context.DeadlineExceeded), ExitCodeTimeout},
+}
+
+func isErr(target error) func(err error) bool {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+// RegisterExitCode maps the errors matching target by errors.Is
+// to code, such as:
+//
+//	cli.RegisterExitCode(ErrNotFound, cli.ExitNoInput)
+//
+// The later registered entries take precedence over the earlier
+// ones and the builtin ones.
+func RegisterExitCode(target error, code int) {
+	RegisterExitCodeFunc(isErr(target), code)
+}
+
+// RegisterExitCodeFunc maps the errors matched by fn to code, it
+// is useful for an error type:
+//
+//	cli.RegisterExitCodeFunc(func(err error) bool {
+//		var e *os.PathError
+//		return errors.As(err, &e)
+//	}, cli.ExitIOErr)
+func RegisterExitCodeFunc(match func(err error) bool, code int) {
+	exitCodes.Lock()
+	defer exitCodes.Unlock()
+	exitCodes.entries = append(exitCodes.entries, exitCodeEntry{match, code})
+}
+
+// ExitCodeOf returns the exit code of err: 0 for nil, the code
+// of an ExitCoder in the chain, the registered code, or
+// ExitFailure for an unknown error.
+func ExitCodeOf(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	// errors.Is doesn't work well with the errors.v3 container,
+	// so the errors in a container are tested one by one, the
+	// first known one wins.
+	if children := childrenOf(err); len(children) > 0 {
+		for _, c := range children {
+			if code := ExitCodeOf(c); code != ExitFailure {
+				return code
+			}
+		}
+		return ExitFailure
+	}
+
+	var ec ExitCoder
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+
+	exitCodes.RLock()
+	entries := exitCodes.entries
+	exitCodes.RUnlock()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].match(err) {
+			return entries[i].code
+		}
+	}
+	for _, e := range builtinExitCodes {
+		if e.match(err) {
+			return e.code
+		}
+	}
+	return ExitFailure
+}
+
+// ExitCodeName returns the short name of code, such as "usage"
+// for ExitUsage.
+func ExitCodeName(code int) string {
+	switch code {
+	case ExitOK:
+		return "ok"
+	case ExitNotRunning:
+		return "notrunning"
+	case ExitUsage:
+		return "usage"
+	case ExitDataErr:
+		return "dataerr"
+	case ExitNoInput:
+		return "noinput"
+	case ExitUnavailable:
+		return "unavailable"
+	case ExitSoftware:
+		return "software"
+	case ExitIOErr:
+		return "ioerr"
+	case ExitTempFail:
+		return "tempfail"
+	case ExitNoPerm:
+		return "noperm"
+	case ExitConfig:
+		return "config"
+	case ExitCodeTimeout:
+		return "timeout"
+	}
+	return "error"
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type codedErr struct{}
+
+func (codedErr) Error() string   { return "coded" }
+func (codedErr) ExitCode() int   { return 42 }
+func (codedErr) Hints() []string { return []string{"try again"} }
+
+func TestExitCodeOf(t *testing.T) {
+	errNotFound := errors.New("not found")
+	defer func() { exitCodes.entries = nil }()
+	RegisterExitCode(errNotFound, ExitNoInput)
+
+	for i, c := range []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.New("unknown"), ExitFailure},
+		{LocalizeError(MsgErrRequiredFlag, ErrRequiredFlag, "file", "app"), ExitUsage},
+		{fmt.Errorf("wrapped: %w", ErrUnmatchedFlag.FormatWith("--x", "app")), ExitUsage},
+		{&TimeoutError{Timeout: 1}, ExitCodeTimeout},
+		{fmt.Errorf("open: %w", errNotFound), ExitNoInput},
+		{errors.Join(errors.New("a"), codedErr{}), 42},
+		{errors.Join(codedErr{}, ErrRequiredFlag), 42}, // the first known one wins
+	} {
+		if got := ExitCodeOf(c.err); got != c.want {
+			t.Fatalf("%d. expecting exit code %d for %v, got %d", i, c.want, c.err, got)
+		}
+	}
+
+	// the later registered entry takes precedence
+	RegisterExitCode(errNotFound, ExitDataErr)
+	if got := ExitCodeOf(errNotFound); got != ExitDataErr {
+		t.Fatalf("expecting exit code %d, got %d", ExitDataErr, got)
+	}
+}
+
+func TestNewErrorReport(t *testing.T) {
+	root := rootCmdForTesting()
+	c, _ := root.DottedPathToCommandOrFlag("server.start")
+	cc := c.(*CmdS)
+
+	err := errors.Join(
+		LocalizeError(MsgErrRequiredFlag, ErrRequiredFlag, "foreground", "start"),
+		fmt.Errorf("cannot bind: %w", codedErr{}),
+	)
+	r := NewErrorReport(err, cc)
+	if r.Code != ExitUsage || r.Kind != "usage" || r.Command != "server.start" {
+		t.Fatalf("bad report: %+v", r)
+	}
+	if !strings.Contains(r.Message, "foreground") {
+		t.Fatalf("expecting the first error as the concise message, got %q", r.Message)
+	}
+	if len(r.Chain) != 3 || !strings.HasPrefix(r.Chain[2], "  coded") {
+		t.Fatalf("expecting the chain in 3 lines, got %q", r.Chain)
+	}
+	if len(r.Hints) != 2 || r.Hints[0] != "try again" {
+		t.Fatalf("expecting the hints of ErrorHinter and usage, got %q", r.Hints)
+	}
+
+	r = NewErrorReport(ErrUnmatchedFlag.FormatWith("--x", "start"), cc)
+	var sb strings.Builder
+	_ = r.WriteText(&sb, false)
+	if out := sb.String(); !strings.HasPrefix(out, "error: UNKNOWN Flag FOUND") ||
+		!strings.Contains(out, "hint: Run '"+root.AppName+" server start --help' for usage.") {
+		t.Fatalf("unexpected text form:\n%s", out)
+	}
+
+	var buf bytes.Buffer
+	_ = r.WriteJSON(&buf)
+	var m map[string]any
+	if e := json.Unmarshal(buf.Bytes(), &m); e != nil {
+		t.Fatal(e)
+	}
+	if m["code"] != float64(ExitUsage) || m["kind"] != "usage" || m["command"] != "server.start" {
+		t.Fatalf("unexpected json form: %s", buf.String())
+	}
+}
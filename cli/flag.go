@@ -97,7 +97,7 @@ type transFunc func(ss string, clr color.Color) string
 
 func (f *Flag) DefaultValueHelpString(trans transFunc, clr, clrDefault color.Color) (hs, plain string) {
 	if f.defaultValue != nil {
-		val := f.defaultValue
+		val := f.MaskedValue(f.defaultValue)
 		// conf := f.Store()
 		// title := f.Title()
 		// if node, br, pm, found := conf.Locate(title, nil); found && !pm && !br && node != nil {
@@ -116,6 +116,36 @@ func (f *Flag) DefaultValueHelpString(trans transFunc, clr, clrDefault color.Col
 	return
 }
 
+// MaskedValue returns SecretMask for a non-empty value if this
+// flag is secret, or returns val as is.
+func (f *Flag) MaskedValue(val any) any {
+	if f.secret && val != nil && fmt.Sprint(val) != "" {
+		return SecretMask
+	}
+	return val
+}
+
+// Desc returns the description, translated by the message
+// catalog with ID 'flg.<dotted.path>.desc', see RegisterMessages.
+func (f *Flag) Desc() string {
+	return localizedText("flg", &f.BaseOpt, "desc", f.BaseOpt.Desc())
+}
+
+// DescLong returns the long description, translated by the
+// message catalog with ID 'flg.<dotted.path>.long'.
+func (f *Flag) DescLong() string {
+	if f.longDesc == "" {
+		return f.Desc()
+	}
+	return localizedText("flg", &f.BaseOpt, "long", f.longDesc)
+}
+
+// Examples returns the examples, translated by the message
+// catalog with ID 'flg.<dotted.path>.examples'.
+func (f *Flag) Examples() string {
+	return localizedText("flg", &f.BaseOpt, "examples", f.examples)
+}
+
 func (f *Flag) ToggleGroup() string        { return f.toggleGroup }
 func (f *Flag) PlaceHolder() string        { return f.placeHolder }
 func (f *Flag) DefaultValue() any          { return f.defaultValue }
@@ -125,6 +155,7 @@ func (f *Flag) ValidArgs() []string        { return f.validArgs }
 func (f *Flag) Range() (min, max int)      { return f.min, f.max }
 func (f *Flag) HeadLike() bool             { return f.headLike }
 func (f *Flag) Required() bool             { return f.required }
+func (f *Flag) Secret() bool               { return f.secret }
 func (f *Flag) JustOnce() bool             { return f.justOnce }
 func (f *Flag) ActionStr() string          { return f.actionStr }
 func (f *Flag) MutualExclusives() []string { return f.mutualExclusives }
@@ -149,6 +180,7 @@ func (f *Flag) AppendValidArgs(validArgs ...string)     { f.validArgs = append(f
 func (f *Flag) SetRange(min, max int)                   { f.min, f.max = min, max }
 func (f *Flag) SetHeadLike(headLike bool)               { f.headLike = headLike }
 func (f *Flag) SetRequired(required bool)               { f.required = required }
+func (f *Flag) SetSecret(secret bool)                   { f.secret = secret }
 func (f *Flag) SetJustOnce(justOnce bool)               { f.justOnce = justOnce }
 func (f *Flag) SetActionStr(action string)              { f.actionStr = action }
 func (f *Flag) SetMutualExclusives(ex ...string)        { f.mutualExclusives = ex }
@@ -552,6 +584,7 @@ func (f *Flag) Clone() any {
 		max:            f.max,
 		headLike:       f.headLike,
 		required:       f.required,
+		secret:         f.secret,
 
 		onParseValue: f.onParseValue,
 		onMatched:    f.onMatched,
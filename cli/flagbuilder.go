@@ -50,6 +50,18 @@ type FlagBuilder interface {
 	// In a colorful console, the deprecated commands and
 	// flags will be shown with strike-through line.
 	Deprecated(deprecated string) FlagBuilder
+	// Notes appends the extra notes, which will be shown in the
+	// Notes section of help screen, manpage and docs.
+	Notes(notes ...string) FlagBuilder
+	// SeeAlso appends the related commands by dotted path, such
+	// as "server.start".
+	SeeAlso(dottedPaths ...string) FlagBuilder
+	// Since is a version string just like 'v1.4', that means this
+	// flag was added in `v1.4`.
+	//
+	// The flag will be hidden if the builtin `--version-sim`
+	// specifies an older version.
+	Since(version string) FlagBuilder
 	// Hidden command/flag won't be shown in help-screen and others output.
 	//
 	// The Hidden command/flag may be printed normally if very verbose mode
@@ -115,6 +127,21 @@ type FlagBuilder interface {
 	//
 	// Once end-user input missed, an error will be thrown up.
 	Required(required bool) FlagBuilder
+	// Secret identifies this flag holds a sensitive value, such
+	// as a password or an api token.
+	//
+	// A secret flag will be masked in help screen, `~~debug`
+	// outputs and logs, and it will never be written back to
+	// the config files.
+	//
+	// If the flag is given without a value, cmdr prompts
+	// end-user to input it without echo when stdin is a tty.
+	// A value starting with '-' or '~' is accepted unless it's
+	// a known flag.
+	//
+	// The value can be an indirection, such as `file:/path`
+	// or `env:NAME`. See also SecretResolver.
+	Secret(b ...bool) FlagBuilder
 
 	// CompJustOnce is used for zsh completion.
 	CompJustOnce(justOnce bool) FlagBuilder
@@ -957,6 +957,8 @@ func (*workerS) Args() []string                                     { return nil
 func (w *workerS) SuggestRetCode() int                              { return w.retCode } //
 func (w *workerS) ParsedState() ParsedState                         { return nil }
 func (w *workerS) LoadedSources() (results []LoadedSources)         { return }
+func (w *workerS) Profile() string                                  { return "" }
+func (w *workerS) Phase() Phase                                     { return PhaseIdle }
 
 func (w *workerS) SetCancelFunc(cancelFunc func()) {}
 func (w *workerS) CancelFunc() func()              { return nil }
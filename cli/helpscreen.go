@@ -0,0 +1,103 @@
+package cli
+
+import "context"
+
+// HelpPainter renders a help screen.
+//
+// By default, cmdr renders the help screen with its builtin
+// colorful layout. Install your own painter by
+// cmdr.WithHelpPainter() to ship a branded or compact layout:
+//
+//	app := cmdr.New(cmdr.WithHelpPainter(cli.HelpPainterFunc(
+//		func(ctx context.Context, wr cli.HelpWriter, screen *cli.HelpScreen) (err error) {
+//			_, _ = wr.WriteString(screen.Usage + "\n")
+//			for _, grp := range screen.CommandGroups {
+//				for _, row := range grp.Commands {
+//					_, _ = wr.WriteString(fmt.Sprintf("  %-16s %s\n", row.Titles[0], row.Description))
+//				}
+//			}
+//			return
+//		})))
+//
+// The texts in HelpScreen have been expanded (envvars and
+// templates like `{{.AppName}}`), but the markups such as
+// `<code>` and `<b>` are kept. A painter can render them by
+// color.GetCPT().Translate() from 'github.com/hedzr/is/term/color'.
+type HelpPainter interface {
+	Paint(ctx context.Context, wr HelpWriter, screen *HelpScreen) (err error)
+}
+
+// HelpPainterFunc adapts a function to a HelpPainter.
+type HelpPainterFunc func(ctx context.Context, wr HelpWriter, screen *HelpScreen) (err error)
+
+func (f HelpPainterFunc) Paint(ctx context.Context, wr HelpWriter, screen *HelpScreen) (err error) {
+	return f(ctx, wr, screen)
+}
+
+// HelpScreen is the data model of a help screen, which is
+// passed to a HelpPainter.
+type HelpScreen struct {
+	Cmd         Cmd    // the command which the help screen is for
+	AppName     string //
+	Version     string //
+	Header      string // the header of root command, see also RootCommand.Header()
+	Usage       string // such as `$ app server start [Options...][files...]`
+	Description string // the long description of Cmd
+	Examples    string //
+	Notes       []string
+	SeeAlso     []string // the related commands, such as `app server start`
+	Since       string   // the version which Cmd was added in
+	Footer      string   // the footer of root command
+
+	CommandGroups []HelpCommandGroup // the subcommands of Cmd, grouped
+	FlagSections  []HelpFlagSection  // the flags of Cmd and its parents, from Cmd to root
+	Topics        []*HelpTopic       // the help topics, for root command only
+}
+
+// HelpCommandGroup is a group of subcommands. Title is empty
+// for the unsorted group.
+type HelpCommandGroup struct {
+	Title    string
+	Commands []HelpCommandRow
+}
+
+// HelpCommandRow is a subcommand row in a help screen.
+type HelpCommandRow struct {
+	Cmd         Cmd
+	Titles      []string // long, short and aliases
+	Description string
+	Deprecated  string // the deprecated version, or empty
+	Since       string // the version which the command was added in
+	Hidden      bool   // a hidden command is shown in verbose mode only
+}
+
+// HelpFlagSection collects the flags of a command, such as
+// "Flags", "Parent Flags" or "Global Flags".
+type HelpFlagSection struct {
+	Title  string
+	Owner  Cmd
+	Level  int // 0 for Cmd itself, 1 for its parent, and so on
+	Groups []HelpFlagGroup
+}
+
+// HelpFlagGroup is a group of flags. Title is empty for the
+// unsorted group.
+type HelpFlagGroup struct {
+	Title string
+	Flags []HelpFlagRow
+}
+
+// HelpFlagRow is a flag row in a help screen.
+type HelpFlagRow struct {
+	Flag        *Flag
+	Titles      []string // long, short and aliases
+	PlaceHolder string
+	Description string
+	Default     any      // the default value, a secret one is masked
+	EnvVars     []string //
+	ToggleGroup string   //
+	Deprecated  string   // the deprecated version, or empty
+	Since       string   // the version which the flag was added in
+	Required    bool     //
+	Hidden      bool     // a hidden flag is shown in verbose mode only
+}
@@ -0,0 +1,37 @@
+package cli
+
+// HelpTopic is a non-command help page, such as
+// `app help environment` or `app help config-format`.
+//
+// Register the topics by cmdr.WithHelpTopics(). They are listed
+// in the "Help Topics" section of root help screen, and emitted
+// as extra pages by the manpage and markdown doc generators.
+//
+// Body is Markdown-ish text: headings (`# Title`), bullets
+// (`- item`), fenced code blocks, inline `code`, **bold**,
+// *italic* and ==mark== are recognized, and the color tags such
+// as `<code>` and `<mark>` can be used directly.
+type HelpTopic struct {
+	Name        string   // such as "config-format"
+	Aliases     []string //
+	Description string   // one-line description, shown in "Help Topics" section
+	Body        string   // Markdown-ish text
+}
+
+// Match tests if name is the name or an alias of the topic.
+func (t *HelpTopic) Match(name string) bool {
+	if t.Name == name {
+		return true
+	}
+	for _, a := range t.Aliases {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Titles returns the name and aliases.
+func (t *HelpTopic) Titles() []string {
+	return append([]string{t.Name}, t.Aliases...)
+}
@@ -0,0 +1,494 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// The message IDs of the builtin labels and messages.
+//
+// An app can override them, or translate them into another
+// language, by [RegisterMessages].
+const (
+	MsgUsage               = "label.usage"
+	MsgDescription         = "label.description"
+	MsgExamples            = "label.examples"
+	MsgNotes               = "label.notes"
+	MsgSeeAlso             = "label.see-also"
+	MsgCommands            = "label.commands"
+	MsgFlags               = "label.flags"
+	MsgGlobalFlags         = "label.global-flags"
+	MsgParentFlags         = "label.parent-flags"
+	MsgGrandpaFlags        = "label.grandpa-flags"
+	MsgHelpTopics          = "label.help-topics"
+	MsgOptions             = "label.options"
+	MsgRedirectedRoot      = "note.redirected-root"
+	MsgAllRedirected       = "note.all-redirected"
+	MsgAvailableSince      = "note.available-since"
+	MsgFlagAvailableSince  = "note.flag-available-since"
+	MsgRedirectedTo        = "note.redirected-to"
+	MsgRedirectedFrom      = "note.redirected-from"
+	MsgSince               = "label.since"
+	MsgCommandsOf          = "label.commands-of"
+	MsgEnvironment         = "label.environment"
+	MsgErrUnmatchedCommand = "err.unmatched-command"
+	MsgErrUnmatchedFlag    = "err.unmatched-flag"
+	MsgErrRequiredFlag     = "err.required-flag"
+	MsgErrValidArgs        = "err.valid-args"
+	MsgErrMissedPrereq     = "err.missed-prerequisite"
+	MsgErrFlagJustOnce     = "err.flag-just-once"
+	MsgErrSecretResolving  = "err.secret-resolving"
+	MsgErrTimeout          = "err.timeout"
+	MsgErrorLabel          = "label.error"
+	MsgHintLabel           = "label.hint"
+	MsgHintUsage           = "hint.usage"
+	MsgHintTimeout         = "hint.timeout"
+	MsgPromptChoose        = "prompt.choose"
+	MsgPromptInvalid       = "prompt.invalid"
+	MsgPromptOutOfRange    = "prompt.out-of-range"
+	MsgPromptConfirm       = "prompt.confirm"
+	MsgErrConfirmRequired  = "err.confirm-required"
+	MsgErrNotConfirmed     = "err.not-confirmed"
+	MsgDryRun              = "note.dry-run"
+	MsgSearchNoMatch       = "note.search-no-match"
+	MsgSearchResults       = "label.search-results"
+	MsgSearchFlags         = "label.search-flags"
+	MsgHintHelpTopic       = "hint.help-topic"
+)
+
+// DefaultLocale is the locale of the builtin messages.
+const DefaultLocale = "en"
+
+var catalog = struct {
+	sync.RWMutex
+	messages map[string]map[string]string // locale -> id -> text, registered by app
+	locale   string
+}{messages: make(map[string]map[string]string)}
+
+// builtinMessages holds the builtin translations, read-only.
+var builtinMessages = map[string]map[string]string{
+	"en": builtinMessagesEn,
+	"zh": builtinMessagesZh,
+	"de": builtinMessagesDe,
+}
+
+// RegisterMessages adds the translations for a locale into the
+// message catalog, the existing entries are overridden.
+//
+// The keys are message IDs. Besides the builtin IDs (such as
+// [MsgUsage]), the descriptions of commands and flags can be
+// translated by the IDs in the form of:
+//
+//	cmd.<dotted.path>.desc       // Desc() of command 'dotted path'
+//	cmd.<dotted.path>.long       // DescLong()
+//	cmd.<dotted.path>.examples   // Examples()
+//	flg.<dotted.path>.desc       // Desc() of flag, such as 'flg.server.start.port.desc'
+//	group.<title>                // the group title, such as 'group.Misc'
+//
+// The root command uses 'cmd.desc', and so on. For example:
+//
+//	cli.RegisterMessages("de", map[string]string{
+//		cli.MsgUsage:           "Aufruf",
+//		"cmd.server.desc":      "Server-Befehle",
+//		"flg.server.port.desc": "Der Port des Servers",
+//	})
+//
+// The locale is a language tag like 'zh', 'zh-CN' or 'de-AT'.
+// While looking up, 'zh-CN' falls back to 'zh', and then the
+// builtin English messages.
+func RegisterMessages(locale string, msgs map[string]string) {
+	if locale = NormalizeLocale(locale); locale == "" {
+		locale = DefaultLocale
+	}
+	catalog.Lock()
+	defer catalog.Unlock()
+	m := catalog.messages[locale]
+	if m == nil {
+		m = make(map[string]string, len(msgs))
+		catalog.messages[locale] = m
+	}
+	for k, v := range msgs {
+		m[k] = v
+	}
+}
+
+// SetLocale sets the current locale. An empty string resets it,
+// see [Locale].
+func SetLocale(locale string) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	catalog.locale = NormalizeLocale(locale)
+}
+
+// Locale returns the current locale, such as 'en', 'zh-CN'.
+//
+// If no locale is set, it is detected from environment by
+// [DetectLocale], but only if the app has registered its own
+// messages by [RegisterMessages]. So an app without
+// translations keeps the builtin English labels, rather than
+// mixing the translated labels with its English descriptions.
+func Locale() string {
+	catalog.RLock()
+	locale, registered := catalog.locale, len(catalog.messages) > 0
+	catalog.RUnlock()
+	if locale == "" {
+		if !registered {
+			return DefaultLocale
+		}
+		locale = DetectLocale()
+	}
+	return locale
+}
+
+// DetectLocale detects the locale from the envvars LC_ALL,
+// LC_MESSAGES and LANG, in that order. It returns DefaultLocale
+// if none of them is set, or it is 'C' or 'POSIX'.
+func DetectLocale() string {
+	for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(k); v != "" {
+			if locale := NormalizeLocale(v); locale != "" {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// NormalizeLocale converts a POSIX locale name such as
+// 'zh_CN.UTF-8' or 'de_DE@euro' to a language tag like 'zh-CN'
+// and 'de-DE'. 'C' and 'POSIX' are treated as empty.
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// fallbacks returns locale and its parents, such as 'zh-Hans-CN',
+// 'zh-Hans' and 'zh'.
+func fallbacks(locale string) (list []string) {
+	for locale != "" {
+		list = append(list, locale)
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return
+}
+
+// lookup finds the message of id in locale and its fallbacks,
+// the registered messages take precedence over the builtin ones.
+func lookup(locale, id string) (text string, ok bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	for _, l := range fallbacks(locale) {
+		if text, ok = catalog.messages[l][id]; ok {
+			return
+		}
+		if text, ok = builtinMessages[l][id]; ok {
+			return
+		}
+	}
+	return
+}
+
+// hasTranslations tests if there are any messages which may
+// replace the original English texts in locale.
+func hasTranslations(locale string) bool {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	for _, l := range fallbacks(locale) {
+		if len(catalog.messages[l]) > 0 || (l != DefaultLocale && len(builtinMessages[l]) > 0) {
+			return true
+		}
+	}
+	return len(catalog.messages[DefaultLocale]) > 0
+}
+
+// T returns the message of id in current locale. It falls back
+// to the builtin English message, or id itself if not found.
+func T(id string) string {
+	if text, ok := lookup(Locale(), id); ok {
+		return text
+	}
+	if text, ok := lookup(DefaultLocale, id); ok {
+		return text
+	}
+	return id
+}
+
+// Tf formats the message of id with args, like fmt.Sprintf.
+func Tf(id string, args ...any) string {
+	return fmt.Sprintf(T(id), args...)
+}
+
+// localized returns the message of id in current locale, or def
+// if there is no translation.
+func localized(id, def string) string {
+	locale := Locale()
+	if !hasTranslations(locale) {
+		return def
+	}
+	if text, ok := lookup(locale, id); ok {
+		return text
+	}
+	if text, ok := lookup(DefaultLocale, id); ok {
+		return text
+	}
+	return def
+}
+
+// localizedText returns the translation of a field of command
+// or flag c, or def if there is no translation.
+func localizedText(kind string, c *BaseOpt, field, def string) string {
+	if !hasTranslations(Locale()) {
+		return def
+	}
+	return localized(msgIDOf(kind, c.GetDottedPath(), field), def)
+}
+
+// msgIDOf returns the message ID of a command or a flag, such
+// as 'cmd.server.start.desc'.
+func msgIDOf(kind, dottedPath, field string) string {
+	if dottedPath == "" {
+		return kind + "." + field
+	}
+	return kind + "." + dottedPath + "." + field
+}
+
+// LocalizeError returns a copy of err formatted with args, see
+// errors.v3 FormatWith. Its message is translated by the message
+// catalog with id, and [errors.Is] still works with the
+// original err.
+func LocalizeError(id string, err interface{ FormatWith(args ...any) error }, args ...any) error {
+	return &localizedError{id: id, err: err.FormatWith(args...), args: args}
+}
+
+type localizedError struct {
+	id   string
+	err  error
+	args []any
+}
+
+func (e *localizedError) Error() string {
+	if text, ok := lookup(Locale(), e.id); ok {
+		return fmt.Sprintf(text, e.args...)
+	}
+	return e.err.Error()
+}
+
+func (e *localizedError) Unwrap() error { return e.err }
+
+var builtinMessagesEn = map[string]string{
+	MsgUsage:               "Usage",
+	MsgDescription:         "Description",
+	MsgExamples:            "Examples",
+	MsgNotes:               "Notes",
+	MsgSeeAlso:             "See Also",
+	MsgCommands:            "Commands",
+	MsgFlags:               "Flags",
+	MsgGlobalFlags:         "Global Flags",
+	MsgParentFlags:         "Parent Flags",
+	MsgGrandpaFlags:        "Grandpa Flags",
+	MsgHelpTopics:          "Help Topics",
+	MsgOptions:             "Options...",
+	MsgRedirectedRoot:      "<i>Root Command was been redirected to Subcommand</i>: \"<b>%s</b>\"",
+	MsgAllRedirected:       "All Redirected Commands",
+	MsgAvailableSince:      "Available since <b>%s</b>.",
+	MsgFlagAvailableSince:  "available since <b>%s</b>.",
+	MsgRedirectedTo:        "<i>This Command was been redirected to</i>: \"<b>%s</b>\"",
+	MsgRedirectedFrom:      "These commands redirect to here:",
+	MsgSince:               "Since %s",
+	MsgCommandsOf:          "Commands of %s",
+	MsgEnvironment:         "Environment",
+	MsgErrUnmatchedCommand: "UNKNOWN CmdS FOUND: %q | cmd=%v",
+	MsgErrUnmatchedFlag:    "UNKNOWN Flag FOUND: %q | cmd=%v",
+	MsgErrRequiredFlag:     "Flag %q is REQUIRED | cmd=%v",
+	MsgErrValidArgs:        "Flag %q expects a valid input is in list: %v | cmd=%v",
+	MsgErrMissedPrereq:     "Flag %q needs %q was set at first",
+	MsgErrFlagJustOnce:     "Flag %q MUST BE set once only",
+	MsgErrSecretResolving:  "Flag %q cannot be resolved: %v",
+	MsgErrTimeout:          "Command %q timed out after %v",
+	MsgErrorLabel:          "error",
+	MsgHintLabel:           "hint",
+	MsgHintUsage:           "Run '%s --help' for usage.",
+	MsgHintTimeout:         "Use '--timeout DURATION' to allow more time.",
+	MsgPromptChoose:        "Choose one of (number or value):",
+	MsgPromptInvalid:       "Invalid value %q: %v",
+	MsgPromptOutOfRange:    "should be in range [%d, %d]",
+	MsgPromptConfirm:       "%s. Continue? [y/N]: ",
+	MsgErrConfirmRequired:  "Command %q needs a confirmation, use '--yes' to proceed",
+	MsgErrNotConfirmed:     "Command %q was not confirmed",
+	MsgDryRun:              "[dry-run] %s",
+	MsgSearchNoMatch:       "No commands or flags matched %q.",
+	MsgSearchResults:       "Search results for %q:",
+	MsgSearchFlags:         "flags: %s",
+	MsgHintHelpTopic:       "Type '%s help TOPIC' to read a topic.",
+}
+
+var builtinMessagesZh = map[string]string{
+	MsgUsage:               "用法",
+	MsgDescription:         "描述",
+	MsgExamples:            "示例",
+	MsgNotes:               "注意",
+	MsgSeeAlso:             "参见",
+	MsgCommands:            "命令",
+	MsgFlags:               "选项",
+	MsgGlobalFlags:         "全局选项",
+	MsgParentFlags:         "上级选项",
+	MsgGrandpaFlags:        "祖先选项",
+	MsgHelpTopics:          "帮助主题",
+	MsgOptions:             "选项...",
+	MsgRedirectedRoot:      "<i>根命令已被重定向到子命令</i>: \"<b>%s</b>\"",
+	MsgAllRedirected:       "所有被重定向的命令",
+	MsgAvailableSince:      "自 <b>%s</b> 起可用。",
+	MsgFlagAvailableSince:  "自 <b>%s</b> 起可用。",
+	MsgRedirectedTo:        "<i>此命令已被重定向到</i>: \"<b>%s</b>\"",
+	MsgRedirectedFrom:      "以下命令被重定向到这里：",
+	MsgSince:               "自 %s 起",
+	MsgCommandsOf:          "%s 的命令",
+	MsgEnvironment:         "环境变量",
+	MsgErrUnmatchedCommand: "未知的命令: %q | cmd=%v",
+	MsgErrUnmatchedFlag:    "未知的选项: %q | cmd=%v",
+	MsgErrRequiredFlag:     "必须指定选项 %q | cmd=%v",
+	MsgErrValidArgs:        "选项 %q 的值应该是以下之一: %v | cmd=%v",
+	MsgErrMissedPrereq:     "选项 %q 需要先设置 %q",
+	MsgErrFlagJustOnce:     "选项 %q 只能设置一次",
+	MsgErrSecretResolving:  "选项 %q 无法解析: %v",
+	MsgErrTimeout:          "命令 %q 在 %v 后超时",
+	MsgErrorLabel:          "错误",
+	MsgHintLabel:           "提示",
+	MsgHintUsage:           "运行 '%s --help' 查看用法。",
+	MsgHintTimeout:         "使用 '--timeout DURATION' 延长时限。",
+	MsgPromptChoose:        "请选择（序号或值）：",
+	MsgPromptInvalid:       "无效的值 %q：%v",
+	MsgPromptOutOfRange:    "应在范围 [%d, %d] 内",
+	MsgPromptConfirm:       "%s。是否继续？[y/N]：",
+	MsgErrConfirmRequired:  "命令 %q 需要确认，使用 '--yes' 继续",
+	MsgErrNotConfirmed:     "命令 %q 未被确认",
+	MsgDryRun:              "[演习] %s",
+	MsgSearchNoMatch:       "没有匹配 %q 的命令或选项。",
+	MsgSearchResults:       "%q 的搜索结果：",
+	MsgSearchFlags:         "选项：%s",
+	MsgHintHelpTopic:       "输入 '%s help TOPIC' 阅读一个主题。",
+
+	"group.Misc":       "杂项",
+	"group.Addons":     "插件",
+	"group.Extensions": "扩展",
+	"group.Aliases":    "别名",
+
+	"cmd.version.desc":         "显示应用的版本信息",
+	"cmd.help.desc":            "显示命令的帮助系统",
+	"cmd.generate.desc":        "为本应用生成辅助文件",
+	"cmd.generate.manual.desc": "生成 Linux 手册页",
+	"cmd.generate.doc.desc":    "生成文档",
+	"cmd.generate.shell.desc":  "生成或安装 shell 自动补全脚本",
+	"cmd.sbom.desc":            "显示 SBOM 信息",
+	"flg.version.desc":         "显示应用的版本信息",
+	"flg.version-sim.desc":     "模拟本应用的一个版本号",
+	"flg.built-info.desc":      "显示本应用的构建信息",
+	"flg.help.desc":            "显示此帮助屏幕 (-?)",
+	"flg.help.search.desc":     "搜索命令、选项和帮助文本",
+	"flg.manual.desc":          "以手册页格式显示帮助屏幕 (需要安装!)",
+	"flg.tree.desc":            "以树形列出命令和选项",
+	"flg.no-pager.desc":        "不使用分页器 ($PAGER 或 'less -R') 显示长的帮助屏幕",
+	"flg.config.desc":          "加载你的配置文件",
+	"flg.profile.desc":         "激活一个配置方案 (叠加 'profiles.NAME.*')",
+	"flg.lang.desc":            "界面语言，例如 en, zh-CN, de",
+	"flg.verbose.desc":         "以详细模式显示更多的进度或调试信息",
+	"flg.quiet.desc":           "不再输出屏幕信息",
+	"flg.debug.desc":           "进入调试模式",
+	"flg.no-color.desc":        "'<code>cmdr</code>' <i>不输出颜色</i>",
+	"flg.strict-mode.desc":     "'<code>cmdr</code>' 的<mark>严格模式</mark>",
+}
+
+var builtinMessagesDe = map[string]string{
+	MsgUsage:               "Aufruf",
+	MsgDescription:         "Beschreibung",
+	MsgExamples:            "Beispiele",
+	MsgNotes:               "Hinweise",
+	MsgSeeAlso:             "Siehe auch",
+	MsgCommands:            "Befehle",
+	MsgFlags:               "Optionen",
+	MsgGlobalFlags:         "Globale Optionen",
+	MsgParentFlags:         "Optionen des übergeordneten Befehls",
+	MsgGrandpaFlags:        "Optionen der Vorfahren",
+	MsgHelpTopics:          "Hilfethemen",
+	MsgOptions:             "Optionen...",
+	MsgRedirectedRoot:      "<i>Der Hauptbefehl wurde auf einen Unterbefehl umgeleitet</i>: \"<b>%s</b>\"",
+	MsgAllRedirected:       "Alle umgeleiteten Befehle",
+	MsgAvailableSince:      "Verfügbar seit <b>%s</b>.",
+	MsgFlagAvailableSince:  "verfügbar seit <b>%s</b>.",
+	MsgRedirectedTo:        "<i>Dieser Befehl wurde umgeleitet auf</i>: \"<b>%s</b>\"",
+	MsgRedirectedFrom:      "Diese Befehle werden hierher umgeleitet:",
+	MsgSince:               "Seit %s",
+	MsgCommandsOf:          "Befehle von %s",
+	MsgEnvironment:         "Umgebung",
+	MsgErrUnmatchedCommand: "Unbekannter Befehl: %q | cmd=%v",
+	MsgErrUnmatchedFlag:    "Unbekannte Option: %q | cmd=%v",
+	MsgErrRequiredFlag:     "Die Option %q ist erforderlich | cmd=%v",
+	MsgErrValidArgs:        "Die Option %q erwartet einen Wert aus der Liste: %v | cmd=%v",
+	MsgErrMissedPrereq:     "Die Option %q setzt voraus, dass %q gesetzt ist",
+	MsgErrFlagJustOnce:     "Die Option %q darf nur einmal gesetzt werden",
+	MsgErrSecretResolving:  "Die Option %q kann nicht aufgelöst werden: %v",
+	MsgErrTimeout:          "Der Befehl %q hat nach %v das Zeitlimit überschritten",
+	MsgErrorLabel:          "Fehler",
+	MsgHintLabel:           "Hinweis",
+	MsgHintUsage:           "Führen Sie '%s --help' aus, um die Verwendung anzuzeigen.",
+	MsgHintTimeout:         "Verwenden Sie '--timeout DURATION', um mehr Zeit zu erlauben.",
+	MsgPromptChoose:        "Wählen Sie eine Option (Nummer oder Wert):",
+	MsgPromptInvalid:       "Ungültiger Wert %q: %v",
+	MsgPromptOutOfRange:    "muss im Bereich [%d, %d] liegen",
+	MsgPromptConfirm:       "%s. Fortfahren? [y/N]: ",
+	MsgErrConfirmRequired:  "Der Befehl %q erfordert eine Bestätigung, verwenden Sie '--yes', um fortzufahren",
+	MsgErrNotConfirmed:     "Der Befehl %q wurde nicht bestätigt",
+	MsgDryRun:              "[Probelauf] %s",
+	MsgSearchNoMatch:       "Keine Befehle oder Optionen passen zu %q.",
+	MsgSearchResults:       "Suchergebnisse für %q:",
+	MsgSearchFlags:         "Optionen: %s",
+	MsgHintHelpTopic:       "Geben Sie '%s help TOPIC' ein, um ein Thema zu lesen.",
+
+	"group.Misc":       "Sonstiges",
+	"group.Addons":     "Erweiterungen",
+	"group.Extensions": "Erweiterungen",
+	"group.Aliases":    "Aliase",
+
+	"cmd.version.desc":         "Versionsinformationen der Anwendung anzeigen",
+	"cmd.help.desc":            "Das Hilfesystem für Befehle anzeigen",
+	"cmd.generate.desc":        "Generatoren für diese Anwendung",
+	"cmd.generate.manual.desc": "Linux-Manpage(s) erzeugen",
+	"cmd.generate.doc.desc":    "Dokumentation erzeugen",
+	"cmd.generate.shell.desc":  "Das Shell-Vervollständigungsskript erzeugen oder installieren",
+	"cmd.sbom.desc":            "SBOM-Informationen anzeigen",
+	"flg.version.desc":         "Versionsinformationen der Anwendung anzeigen",
+	"flg.version-sim.desc":     "Eine vorgetäuschte Version dieser Anwendung simulieren",
+	"flg.built-info.desc":      "Die Build-Informationen dieser Anwendung anzeigen",
+	"flg.help.desc":            "Diese Hilfe anzeigen (-?)",
+	"flg.help.search.desc":     "Befehle, Optionen und Hilfetexte durchsuchen",
+	"flg.manual.desc":          "Die Hilfe im Manpage-Format anzeigen (INSTALLATION NÖTIG!)",
+	"flg.tree.desc":            "Befehle und Optionen als Baum auflisten",
+	"flg.no-pager.desc":        "Lange Hilfeseiten nicht durch einen Pager ($PAGER oder 'less -R') leiten",
+	"flg.config.desc":          "Ihre Konfigurationsdatei laden",
+	"flg.profile.desc":         "Ein Konfigurationsprofil aktivieren ('profiles.NAME.*' überlagern)",
+	"flg.lang.desc":            "Die Sprache der Oberfläche, wie en, zh-CN, de",
+	"flg.verbose.desc":         "Im ausführlichen Modus mehr Fortschritts- und Debug-Informationen anzeigen",
+	"flg.quiet.desc":           "Keine weiteren Bildschirmausgaben",
+	"flg.debug.desc":           "In den Debug-Modus wechseln",
+	"flg.no-color.desc":        "<i>Keine Farben</i> für '<code>cmdr</code>'",
+	"flg.strict-mode.desc":     "<mark>Strikter Modus</mark> für '<code>cmdr</code>'",
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"zh_CN.UTF-8", "zh-CN"},
+		{"de_DE@euro", "de-DE"},
+		{"en", "en"},
+		{"ZH-hans-cn", "zh-hans-CN"},
+		{"C", ""},
+		{"POSIX.UTF-8", ""},
+	} {
+		if got := NormalizeLocale(tc.in); got != tc.want {
+			t.Fatalf("NormalizeLocale(%q) expected %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_AT.UTF-8")
+	if got := DetectLocale(); got != "de-AT" {
+		t.Fatalf("expected 'de-AT', got %q", got)
+	}
+	t.Setenv("LC_ALL", "zh_CN.UTF-8")
+	if got := DetectLocale(); got != "zh-CN" {
+		t.Fatalf("expected 'zh-CN', got %q", got)
+	}
+	t.Setenv("LC_ALL", "C")
+	t.Setenv("LANG", "")
+	if got := DetectLocale(); got != DefaultLocale {
+		t.Fatalf("expected %q, got %q", DefaultLocale, got)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	defer SetLocale("")
+	saved := catalog.messages
+	defer func() { catalog.messages = saved }()
+	catalog.messages = make(map[string]map[string]string)
+
+	SetLocale("")
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	if got := Locale(); got != DefaultLocale {
+		t.Fatalf("expected %q without the app's messages, got %q", DefaultLocale, got)
+	}
+	RegisterMessages("de", map[string]string{"cmd.desc": "Eine Demo"})
+	if got := Locale(); got != "de-DE" {
+		t.Fatalf("expected 'de-DE' detected, got %q", got)
+	}
+	SetLocale("zh")
+	if got := Locale(); got != "zh" {
+		t.Fatalf("expected 'zh', got %q", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale("")
+
+	SetLocale("en_US")
+	if got := T(MsgUsage); got != "Usage" {
+		t.Fatalf("expected 'Usage', got %q", got)
+	}
+	SetLocale("de-AT") // falls back to 'de'
+	if got := T(MsgUsage); got != "Aufruf" {
+		t.Fatalf("expected 'Aufruf', got %q", got)
+	}
+	SetLocale("fr")
+	if got := T(MsgCommands); got != "Commands" {
+		t.Fatalf("expected 'Commands', got %q", got)
+	}
+	if got := T("no.such.id"); got != "no.such.id" {
+		t.Fatalf("expected the id itself, got %q", got)
+	}
+	RegisterMessages("fr", map[string]string{MsgCommands: "Commandes"})
+	if got := Tf(MsgCommands); got != "Commandes" {
+		t.Fatalf("expected 'Commandes', got %q", got)
+	}
+}
+
+func TestLocalizedDesc(t *testing.T) {
+	defer SetLocale("")
+
+	root := rootCmdForTesting()
+	c, _ := root.DottedPathToCommandOrFlag("server.start")
+	cc := c.(*CmdS)
+	_, ff := root.DottedPathToCommandOrFlag("server.start.foreground")
+	desc, flagDesc := cc.Desc(), ff.Desc()
+
+	RegisterMessages("xx-YY", map[string]string{
+		"cmd.server.start.desc":            "server start in xx",
+		"flg.server.start.foreground.desc": "foreground in xx",
+	})
+	SetLocale("xx_YY.UTF-8")
+	if got := cc.Desc(); got != "server start in xx" {
+		t.Fatalf("expected translated desc, got %q", got)
+	}
+	if got := ff.Desc(); got != "foreground in xx" {
+		t.Fatalf("expected translated flag desc, got %q", got)
+	}
+	if got := cc.DescLong(); cc.longDesc == "" && got != "server start in xx" {
+		t.Fatalf("expected DescLong fallback to translated desc, got %q", got)
+	}
+
+	SetLocale("en")
+	if got := cc.Desc(); got != desc {
+		t.Fatalf("expected original desc %q, got %q", desc, got)
+	}
+	if got := ff.Desc(); got != flagDesc {
+		t.Fatalf("expected original flag desc %q, got %q", flagDesc, got)
+	}
+}
+
+func TestLocalizeError(t *testing.T) {
+	defer SetLocale("")
+
+	SetLocale("en")
+	err := LocalizeError(MsgErrFlagJustOnce, ErrFlagJustOnce, "port")
+	if !errors.Is(err, ErrFlagJustOnce) {
+		t.Fatalf("expecting errors.Is(err, ErrFlagJustOnce), but not: %v", err)
+	}
+	if got := err.Error(); got != `Flag "port" MUST BE set once only` {
+		t.Fatalf("unexpected message: %q", got)
+	}
+
+	SetLocale("de")
+	if got := err.Error(); got != `Die Option "port" darf nur einmal gesetzt werden` {
+		t.Fatalf("unexpected message: %q", got)
+	}
+}
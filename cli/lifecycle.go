@@ -0,0 +1,45 @@
+package cli
+
+// Phase is the lifecycle phase of [Runner.Run].
+//
+// The phases are walked through in order:
+//
+//	PhaseIdle -> PhasePreProcess -> PhaseParse -> PhaseBeforeRun ->
+//	PhaseRun -> PhaseAfterRun -> PhaseCleanup -> PhaseDone
+//
+// A phase may be skipped if an error occurred in the previous
+// ones, except PhaseCleanup, which is always entered so that the
+// tasks of [Config.TasksPostCleanup] can release the resources.
+// PhaseAfterRun is entered only if the action has been invoked.
+//
+// A task can query the current phase by [Runner.Phase].
+type Phase int32
+
+const (
+	PhaseIdle       Phase = iota // Run is not started yet
+	PhasePreProcess              // linking commands, loading config files and envvars
+	PhaseParse                   // TasksBeforeParse, and parsing the command-line
+	PhaseBeforeRun               // TasksParsed, opening the peripherals, and TasksBeforeRun
+	PhaseRun                     // invoking the action of the matched command
+	PhaseAfterRun                // TasksAfterRun
+	PhaseCleanup                 // TasksPostCleanup, always entered
+	PhaseDone                    // Run has returned
+)
+
+var phaseNames = [...]string{
+	PhaseIdle:       "idle",
+	PhasePreProcess: "pre-process",
+	PhaseParse:      "parse",
+	PhaseBeforeRun:  "before-run",
+	PhaseRun:        "run",
+	PhaseAfterRun:   "after-run",
+	PhaseCleanup:    "cleanup",
+	PhaseDone:       "done",
+}
+
+func (p Phase) String() string {
+	if p >= 0 && int(p) < len(phaseNames) {
+		return phaseNames[p]
+	}
+	return "unknown"
+}
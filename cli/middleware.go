@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// Middleware wraps the action of a command for the cross-cutting
+// concerns, such as timing, panic recovery, tracing spans, auth
+// checks and audit logging.
+//
+// Unlike OnPreAction and OnPostAction, a middleware wraps the
+// whole call of the action (including its pre/post actions),
+// so it can skip the action, or change its error:
+//
+//	func Audit(next cli.OnInvokeHandler) cli.OnInvokeHandler {
+//		return func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+//			logz.Info("audit", "cmd", cmd.GetDottedPath(), "args", args)
+//			return next(ctx, cmd, args)
+//		}
+//	}
+//
+// The global middlewares are registered by cmdr.WithMiddlewares(),
+// and the ones for a command subtree by [CommandBuilder.Use].
+// The global ones are the outermost, and then the ones of root
+// command, ..., the matched command.
+type Middleware func(next OnInvokeHandler) OnInvokeHandler
+
+// Chain composes the middlewares around h. The first middleware
+// is the outermost one.
+func Chain(h OnInvokeHandler, mws ...Middleware) OnInvokeHandler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			h = mws[i](h)
+		}
+	}
+	return h
+}
+
+// MiddlewaresOf collects the middlewares added by Use from the
+// root command to cmd, in the order of wrapping.
+func MiddlewaresOf(cmd Cmd) (mws []Middleware) {
+	var stack [][]Middleware
+	for c := cmd; c != nil; c = c.OwnerCmd() {
+		if cc, ok := c.(*CmdS); ok && len(cc.middlewares) > 0 {
+			stack = append(stack, cc.middlewares)
+		}
+		if c.OwnerIsNil() {
+			break
+		}
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		mws = append(mws, stack[i]...)
+	}
+	return
+}
+
+// RecoverMiddleware recovers the panic in an action, and returns
+// it as an error. The stack trace is logged in verbose mode.
+func RecoverMiddleware() Middleware {
+	return func(next OnInvokeHandler) OnInvokeHandler {
+		return func(ctx context.Context, cmd Cmd, args []string) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logz.VerboseContext(ctx, "[cmdr] action panic recovered", "cmd", cmd, "panic", r, "stack", string(debug.Stack()))
+					if e, ok := r.(error); ok {
+						err = fmt.Errorf("panic in action of %v: %w", cmd, e)
+					} else {
+						err = fmt.Errorf("panic in action of %v: %v", cmd, r)
+					}
+				}
+			}()
+			return next(ctx, cmd, args)
+		}
+	}
+}
+
+// ElapsedMiddleware logs the elapsed time of an action.
+func ElapsedMiddleware() Middleware {
+	return func(next OnInvokeHandler) OnInvokeHandler {
+		return func(ctx context.Context, cmd Cmd, args []string) (err error) {
+			start := time.Now()
+			defer func() {
+				logz.InfoContext(ctx, "[cmdr] action elapsed", "cmd", cmd, "elapsed", time.Since(start), "err", err)
+			}()
+			return next(ctx, cmd, args)
+		}
+	}
+}
+
+// TimeoutGrace is the time TimeoutMiddleware waits for an
+// action to return after its deadline.
+var TimeoutGrace = 500 * time.Millisecond
+
+// TimeoutMiddleware gives an action a deadline. The context
+// passed to the action is canceled after d, and the error of
+// the action is a TimeoutError, which matches
+// context.DeadlineExceeded, if it doesn't return in time.
+//
+// An action should watch ctx.Done(). After the deadline, the
+// action has TimeoutGrace to return before the error is returned.
+// Otherwise it keeps running in background while Run is ending,
+// and the peripherals may still be in use when they are closed.
+// A panic raised that late is logged.
+func TimeoutMiddleware(d time.Duration) Middleware {
+	return func(next OnInvokeHandler) OnInvokeHandler {
+		if d <= 0 {
+			return next
+		}
+		return func(ctx context.Context, cmd Cmd, args []string) (err error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			type result struct {
+				err error
+				p   any // the panic, re-raised in caller
+			}
+			done := make(chan result, 1)
+			go func() {
+				var r result
+				defer func() {
+					r.p = recover()
+					done <- r
+				}()
+				r.err = next(ctx, cmd, args)
+			}()
+
+			var r result
+			returned := true
+			select {
+			case r = <-done:
+			case <-ctx.Done():
+				select {
+				case r = <-done: // returned in grace period
+				case <-time.After(TimeoutGrace):
+					returned = false
+					go func() {
+						if r := <-done; r.p != nil {
+							logz.ErrorContext(ctx, "[cmdr] action panicked after its deadline", "cmd", cmd, "panic", r.p)
+						}
+					}()
+				}
+			}
+			if r.p != nil {
+				panic(r.p)
+			}
+			// the action returned nothing but the deadline has
+			// passed, it was most likely stopped by ctx.
+			if (r.err == nil || errors.Is(r.err, context.DeadlineExceeded)) &&
+				errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &TimeoutError{Cmd: cmd, Timeout: d}
+			}
+			if !returned {
+				return ctx.Err()
+			}
+			return r.err
+		}
+	}
+}
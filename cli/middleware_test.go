@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tracing(name string, trace *[]string) Middleware {
+	return func(next OnInvokeHandler) OnInvokeHandler {
+		return func(ctx context.Context, cmd Cmd, args []string) (err error) {
+			*trace = append(*trace, name+">")
+			err = next(ctx, cmd, args)
+			*trace = append(*trace, "<"+name)
+			return
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	var trace []string
+	h := Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) {
+		trace = append(trace, "action")
+		return
+	}, tracing("a", &trace), nil, tracing("b", &trace))
+	_ = h(context.Background(), nil, nil)
+	if want := []string{"a>", "b>", "action", "<b", "<a"}; !reflect.DeepEqual(trace, want) {
+		t.Fatalf("expecting %v, got %v", want, trace)
+	}
+}
+
+func TestMiddlewaresOf(t *testing.T) {
+	var trace []string
+	root := rootCmdForTesting()
+	c, _ := root.DottedPathToCommandOrFlag("server")
+	server := c.(*CmdS)
+	c, _ = root.DottedPathToCommandOrFlag("server.start")
+	start := c.(*CmdS)
+	root.Cmd.(*CmdS).Use(tracing("root", &trace))
+	server.Use(tracing("server", &trace))
+	start.Use(tracing("start", &trace))
+
+	mws := MiddlewaresOf(start)
+	_ = Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) { return }, mws...)(context.Background(), start, nil)
+	if want := []string{"root>", "server>", "start>", "<start", "<server", "<root"}; !reflect.DeepEqual(trace, want) {
+		t.Fatalf("expecting %v, got %v", want, trace)
+	}
+	if n := len(MiddlewaresOf(server)); n != 2 {
+		t.Fatalf("expecting 2 middlewares for 'server', got %d", n)
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	h := Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) {
+		panic("boom")
+	}, RecoverMiddleware())
+	if err := h(context.Background(), nil, nil); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expecting the panic recovered as an error, got %v", err)
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	h := Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+		return
+	}, TimeoutMiddleware(20*time.Millisecond))
+	if err := h(context.Background(), nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expecting context.DeadlineExceeded, got %v", err)
+	}
+	if err := h(context.Background(), nil, nil); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expecting a TimeoutError, got %v", err)
+	}
+
+	errAction := errors.New("action")
+	h = Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) {
+		return errAction
+	}, TimeoutMiddleware(time.Second))
+	if err := h(context.Background(), nil, nil); err != errAction {
+		t.Fatalf("expecting the error of action, got %v", err)
+	}
+
+	h = Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond) // cleaning up in grace period
+		return errAction
+	}, TimeoutMiddleware(20*time.Millisecond))
+	if err := h(context.Background(), nil, nil); err != errAction {
+		t.Fatalf("expecting the error of action returned in grace period, got %v", err)
+	}
+
+	defer func(g time.Duration) { TimeoutGrace = g }(TimeoutGrace)
+	TimeoutGrace = 10 * time.Millisecond
+	late := make(chan struct{})
+	h = Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) {
+		defer close(late)
+		time.Sleep(100 * time.Millisecond) // ignores ctx
+		return
+	}, TimeoutMiddleware(20*time.Millisecond))
+	start := time.Now()
+	if err := h(context.Background(), nil, nil); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expecting a TimeoutError, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Fatalf("expecting returned after the grace period, elapsed %v", elapsed)
+	}
+	<-late
+
+	h = Chain(func(ctx context.Context, cmd Cmd, args []string) (err error) {
+		panic("boom")
+	}, RecoverMiddleware(), TimeoutMiddleware(time.Second))
+	if err := h(context.Background(), nil, nil); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expecting the panic re-raised and recovered, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+)
+
+// Outputter renders the results of an action in the format
+// selected by the builtin flags, so that all commands print
+// their data in the same way:
+//
+//	func listUsers(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+//		var users []User
+//		if users, err = db.Users(ctx); err == nil {
+//			err = cli.Output(ctx).Write(users)
+//		}
+//		return
+//	}
+//
+// The formats are:
+//
+//   - table: aligned columns, one row for each item of a slice,
+//     and the columns are the fields of struct (the json tag name
+//     is preferred) or the keys of map, selected by '--columns'
+//   - json, yaml
+//   - template: a Go text/template given by '--template', which
+//     is executed against the whole value
+//
+// The builtin flags '--output', '--columns' and '--template' are
+// opt-in, see cmdr.WithOutputFormat(). In quiet mode ('--quiet')
+// a table prints the first column only, without the header.
+type Outputter interface {
+	// Write renders v and writes it to the help screen writer,
+	// which is os.Stdout by default.
+	Write(v any) error
+	// Format returns the selected format.
+	Format() string
+}
+
+// The output formats of Outputter.
+const (
+	OutputTable    = "table"
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputTemplate = "template"
+)
+
+// OutputFormats returns the valid values of '--output'.
+func OutputFormats() []string {
+	return []string{OutputTable, OutputJSON, OutputYAML, OutputTemplate}
+}
+
+type ctxKeyOutputter struct{}
+
+// WithOutputter returns a copy of ctx carrying o, which is
+// returned by Output(ctx). cmdr puts its Outputter into the
+// context of the action.
+func WithOutputter(ctx context.Context, o Outputter) context.Context {
+	return context.WithValue(ctx, ctxKeyOutputter{}, o)
+}
+
+// Output returns the Outputter of the action. It writes JSON to
+// os.Stdout if ctx was not passed by cmdr.
+func Output(ctx context.Context) Outputter {
+	if o, ok := ctx.Value(ctxKeyOutputter{}).(Outputter); ok && o != nil {
+		return o
+	}
+	return stdOutputter{}
+}
+
+type stdOutputter struct{}
+
+func (stdOutputter) Format() string { return OutputJSON }
+
+func (stdOutputter) Write(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
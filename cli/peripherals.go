@@ -0,0 +1,323 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hedzr/is/basics"
+
+	"github.com/hedzr/cmdr/v2/internal/tool"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// PeripheralDepender can be implemented by a peripheral to
+// declare the names of the peripherals it depends on, which are
+// opened before it and closed after it.
+type PeripheralDepender interface {
+	DependsOn() []string
+}
+
+// HealthChecker can be implemented by a peripheral to report its
+// health, it's probed by the builtin flag `~~health`.
+type HealthChecker interface {
+	Health(ctx context.Context) (err error)
+}
+
+// ErrUnhealthy is returned by `~~health` if any peripheral isn't
+// healthy.
+var ErrUnhealthy = errors.New("some peripherals are unhealthy")
+
+// HealthResult is the result of probing a peripheral.
+type HealthResult struct {
+	Name    string        `json:"name"`
+	Healthy bool          `json:"healthy"`
+	Skipped bool          `json:"skipped,omitempty"` // no Health(ctx) probe
+	Error   string        `json:"error,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+type peripheralEntry struct {
+	name      string
+	p         basics.Peripheral
+	dependsOn []string
+	opened    bool
+}
+
+// PeripheralManager holds the peripherals of an app, opens them
+// in the order of their dependencies, and closes the opened ones
+// in reverse order.
+//
+// The peripherals are registered by cmdr.WithPeripherals() and
+// cmdr.WithPeripheral(), and opened by cmdr before running the
+// action.
+type PeripheralManager struct {
+	mu      sync.Mutex
+	entries []*peripheralEntry
+}
+
+// NewPeripheralManager returns an empty manager.
+func NewPeripheralManager() *PeripheralManager { return &PeripheralManager{} }
+
+// Add registers p by name. The dependencies are dependsOn plus
+// the ones declared by PeripheralDepender. A peripheral with the
+// same name is replaced.
+func (m *PeripheralManager) Add(name string, p basics.Peripheral, dependsOn ...string) {
+	if d, ok := p.(PeripheralDepender); ok {
+		dependsOn = slices.Concat(dependsOn, d.DependsOn())
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &peripheralEntry{name: name, p: p, dependsOn: dependsOn}
+	for i, x := range m.entries {
+		if x.name == name {
+			m.entries[i] = e
+			return
+		}
+	}
+	m.entries = append(m.entries, e)
+}
+
+// Get returns the peripheral by name, or nil if not found.
+func (m *PeripheralManager) Get(name string) basics.Peripheral {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.entries {
+		if e.name == name {
+			return e.p
+		}
+	}
+	return nil
+}
+
+// Names returns the names of peripherals in opening order.
+func (m *PeripheralManager) Names() (names []string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sorted, _ := m.sorted()
+	for _, e := range sorted {
+		names = append(names, e.name)
+	}
+	return
+}
+
+// Len returns the count of peripherals.
+func (m *PeripheralManager) Len() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// sorted returns the entries in topological order, the ones
+// without dependencies are ordered by name.
+func (m *PeripheralManager) sorted() (list []*peripheralEntry, err error) {
+	byName := make(map[string]*peripheralEntry, len(m.entries))
+	for _, e := range m.entries {
+		byName[e.name] = e
+	}
+	names := make([]string, 0, len(m.entries))
+	for _, e := range m.entries {
+		names = append(names, e.name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(names))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("peripherals have a cyclic dependency: %s", strings.Join(append(path, name), " -> "))
+		}
+		e := byName[name]
+		state[name] = visiting
+		deps := append([]string(nil), e.dependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := byName[dep]; !ok {
+				return fmt.Errorf("peripheral %q depends on %q, which is not registered", name, dep)
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		list = append(list, e)
+		return nil
+	}
+	for _, name := range names {
+		if err = visit(name, nil); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Open opens the peripherals implementing basics.Openable in the
+// order of their dependencies. Each one has timeout (zero means
+// no limit) to get ready.
+//
+// Open stops at the first failure, the peripherals opened so far
+// are still closed by Close, and the one timed out is closed once
+// its Open returns.
+func (m *PeripheralManager) Open(ctx context.Context, timeout time.Duration) (err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var list []*peripheralEntry
+	if list, err = m.sorted(); err != nil {
+		return
+	}
+	for _, e := range list {
+		if e.opened {
+			continue
+		}
+		if o, ok := e.p.(basics.Openable); ok {
+			start := time.Now()
+			if err = openWithTimeout(ctx, e.p, o, timeout); err != nil {
+				return fmt.Errorf("cannot open peripheral %q: %w", e.name, err)
+			}
+			logz.VerboseContext(ctx, "[cmdr] peripheral opened", "name", e.name, "elapsed", time.Since(start))
+		}
+		e.opened = true
+	}
+	return
+}
+
+// openWithTimeout opens p by o within timeout. If o.Open
+// ignores the deadline and succeeds after it, p is closed by the
+// opening goroutine, since Close skips the ones not opened.
+func openWithTimeout(ctx context.Context, p basics.Peripheral, o basics.Openable, timeout time.Duration) (err error) {
+	if timeout <= 0 {
+		return o.Open(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var late bool
+	done := make(chan error, 1)
+	go func() {
+		e := o.Open(ctx)
+		mu.Lock()
+		defer mu.Unlock()
+		if !late {
+			done <- e
+		} else if e == nil {
+			p.Close() // opened after the deadline, nobody owns it
+		}
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		mu.Lock()
+		select {
+		case err = <-done: // opened just in time
+		default:
+			late = true
+			err = fmt.Errorf("startup timed out after %v: %w", timeout, ctx.Err())
+		}
+		mu.Unlock()
+	}
+	return
+}
+
+// Close closes the opened peripherals, and the ones without
+// Open(ctx), in reverse order of opening. Each one has timeout
+// (zero means no limit), and is abandoned once it timed out.
+//
+// If the dependencies are cyclic, nothing has been opened, and
+// the ones without Open(ctx) are closed in reverse order of
+// registration.
+//
+// The manager is empty after closed.
+func (m *PeripheralManager) Close(ctx context.Context, timeout time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	list, err := m.sorted()
+	if err != nil {
+		list = m.entries // cyclic, in registration order
+	}
+	var closing []*peripheralEntry
+	for _, e := range list {
+		if _, openable := e.p.(basics.Openable); e.opened || !openable {
+			closing = append(closing, e)
+		}
+	}
+	m.entries = nil
+	m.mu.Unlock()
+
+	for i := len(closing) - 1; i >= 0; i-- {
+		if e := closing[i]; !tool.CloseWithTimeout(e.p, timeout) {
+			logz.WarnContext(ctx, "[cmdr] closing peripheral timed out", "peripheral", e.name, "timeout", timeout)
+		}
+	}
+}
+
+// Health probes the peripherals implementing HealthChecker in
+// opening order, each one has timeout (zero means no limit).
+func (m *PeripheralManager) Health(ctx context.Context, timeout time.Duration) (results []HealthResult) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	list, err := m.sorted()
+	m.mu.Unlock()
+	if err != nil {
+		return []HealthResult{{Name: "*", Error: err.Error()}}
+	}
+
+	for _, e := range list {
+		r := HealthResult{Name: e.name, Healthy: true}
+		if hc, ok := e.p.(HealthChecker); ok {
+			start := time.Now()
+			if err := probeWithTimeout(ctx, hc, timeout); err != nil {
+				r.Healthy, r.Error = false, err.Error()
+			}
+			r.Elapsed = time.Since(start)
+		} else {
+			r.Skipped = true
+		}
+		results = append(results, r)
+	}
+	return
+}
+
+func probeWithTimeout(ctx context.Context, hc HealthChecker, timeout time.Duration) (err error) {
+	if timeout <= 0 {
+		return hc.Health(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- hc.Health(ctx) }()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakePeripheral struct {
+	name  string
+	trace *[]string
+	deps  []string
+	delay time.Duration
+	errOpen,
+	errHealth error
+}
+
+func (p *fakePeripheral) Open(ctx context.Context) (err error) {
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	*p.trace = append(*p.trace, "open "+p.name)
+	return p.errOpen
+}
+
+func (p *fakePeripheral) Close() { *p.trace = append(*p.trace, "close "+p.name) }
+
+func (p *fakePeripheral) DependsOn() []string { return p.deps }
+
+func (p *fakePeripheral) Health(ctx context.Context) error { return p.errHealth }
+
+func TestPeripheralManager_OpenClose(t *testing.T) {
+	var trace []string
+	m := NewPeripheralManager()
+	m.Add("api", &fakePeripheral{name: "api", trace: &trace, deps: []string{"cache"}})
+	m.Add("cache", &fakePeripheral{name: "cache", trace: &trace}, "db")
+	m.Add("db", &fakePeripheral{name: "db", trace: &trace})
+
+	if want := []string{"db", "cache", "api"}; !reflect.DeepEqual(m.Names(), want) {
+		t.Fatalf("expecting the opening order %v, got %v", want, m.Names())
+	}
+	if err := m.Open(context.Background(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	m.Close(context.Background(), time.Second)
+	want := []string{"open db", "open cache", "open api", "close api", "close cache", "close db"}
+	if !reflect.DeepEqual(trace, want) {
+		t.Fatalf("expecting %v, got %v", want, trace)
+	}
+	if m.Len() != 0 {
+		t.Fatal("expecting the manager empty after closed")
+	}
+}
+
+func TestPeripheralManager_OpenFailed(t *testing.T) {
+	var trace []string
+	m := NewPeripheralManager()
+	m.Add("a", &fakePeripheral{name: "a", trace: &trace})
+	m.Add("b", &fakePeripheral{name: "b", trace: &trace, delay: time.Second}, "a") // timed out
+	m.Add("c", &fakePeripheral{name: "c", trace: &trace}, "b")
+
+	err := m.Open(context.Background(), 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), `"b"`) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expecting the startup timeout of b, got %v", err)
+	}
+	m.Close(context.Background(), 0)
+	if want := []string{"open a", "close a"}; !reflect.DeepEqual(trace, want) {
+		t.Fatalf("expecting the opened ones closed only, %v, got %v", want, trace)
+	}
+}
+
+// stubbornPeripheral ignores the deadline of Open.
+type stubbornPeripheral struct {
+	delay  time.Duration
+	closed chan struct{}
+}
+
+func (p *stubbornPeripheral) Open(ctx context.Context) error { time.Sleep(p.delay); return nil }
+func (p *stubbornPeripheral) Close()                         { close(p.closed) }
+
+func TestPeripheralManager_OpenedLate(t *testing.T) {
+	p := &stubbornPeripheral{delay: 100 * time.Millisecond, closed: make(chan struct{})}
+	m := NewPeripheralManager()
+	m.Add("slow", p)
+
+	if err := m.Open(context.Background(), 20*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expecting the startup timeout, got %v", err)
+	}
+	m.Close(context.Background(), 0)
+	select {
+	case <-p.closed:
+	case <-time.After(time.Second):
+		t.Fatal("expecting the peripheral opened after the deadline closed")
+	}
+}
+
+func TestPeripheralManager_BadDependencies(t *testing.T) {
+	var trace []string
+	m := NewPeripheralManager()
+	m.Add("a", &fakePeripheral{name: "a", trace: &trace}, "b")
+	m.Add("b", &fakePeripheral{name: "b", trace: &trace}, "a")
+	if err := m.Open(context.Background(), 0); err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Fatalf("expecting a cyclic dependency error, got %v", err)
+	}
+
+	m = NewPeripheralManager()
+	m.Add("a", &fakePeripheral{name: "a", trace: &trace}, "nope")
+	if err := m.Open(context.Background(), 0); err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Fatalf("expecting an unknown dependency error, got %v", err)
+	}
+	if len(trace) != 0 {
+		t.Fatalf("expecting nothing opened, got %v", trace)
+	}
+}
+
+type plainCloser struct{}
+
+func (plainCloser) Close() {}
+
+func TestPeripheralManager_Health(t *testing.T) {
+	var trace []string
+	m := NewPeripheralManager()
+	m.Add("db", &fakePeripheral{name: "db", trace: &trace})
+	m.Add("cache", &fakePeripheral{name: "cache", trace: &trace, errHealth: errors.New("refused")})
+	m.Add("log", plainCloser{})
+
+	results := m.Health(context.Background(), time.Second)
+	if len(results) != 3 {
+		t.Fatalf("expecting 3 results, got %+v", results)
+	}
+	for _, r := range results {
+		switch r.Name {
+		case "db":
+			if !r.Healthy || r.Skipped {
+				t.Fatalf("expecting db healthy, got %+v", r)
+			}
+		case "cache":
+			if r.Healthy || r.Error != "refused" {
+				t.Fatalf("expecting cache unhealthy, got %+v", r)
+			}
+		case "log":
+			if !r.Skipped {
+				t.Fatalf("expecting log skipped, got %+v", r)
+			}
+		}
+	}
+	if ExitCodeOf(ErrUnhealthy) != ExitUnavailable {
+		t.Fatalf("expecting exit code %d for ErrUnhealthy", ExitUnavailable)
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretResolver resolves an indirect reference to the real
+// secret value.
+//
+// For example, the value `file:/run/secrets/db-pass` of a
+// secret flag will be resolved by the resolver registered
+// with scheme "file", and ref is "/run/secrets/db-pass".
+//
+// See also FlagBuilder.Secret() and Config.SecretResolvers.
+type SecretResolver func(ctx context.Context, ref string) (value string, err error)
+
+// DefaultSecretResolvers returns the builtin resolvers:
+//
+//   - `file:/path/to/file`: read the secret from a file, the
+//     trailing newline will be stripped.
+//   - `env:NAME`: read the secret from an environment variable,
+//     it's an error if the variable is not set.
+func DefaultSecretResolvers() map[string]SecretResolver {
+	return map[string]SecretResolver{
+		"file": resolveSecretFromFile,
+		"env":  resolveSecretFromEnv,
+	}
+}
+
+func resolveSecretFromFile(ctx context.Context, ref string) (value string, err error) {
+	var data []byte
+	if data, err = os.ReadFile(os.ExpandEnv(ref)); err == nil {
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	_ = ctx
+	return
+}
+
+func resolveSecretFromEnv(ctx context.Context, ref string) (value string, err error) {
+	var ok bool
+	if value, ok = os.LookupEnv(ref); !ok {
+		err = fmt.Errorf("environment variable %q is not set", ref)
+	}
+	_ = ctx
+	return
+}
+
+// ResolveSecret checks if value is an indirection like
+// `scheme:ref` and resolves it with the matched resolver.
+//
+// It returns resolved = false if no resolver matched, and the
+// value should be used as is.
+func ResolveSecret(ctx context.Context, value string, resolvers map[string]SecretResolver) (result string, resolved bool, err error) {
+	pos := strings.IndexRune(value, ':')
+	if pos <= 0 {
+		return value, false, nil
+	}
+	if r, ok := resolvers[value[:pos]]; ok && r != nil {
+		if result, err = r(ctx, value[pos+1:]); err == nil {
+			resolved = true
+		}
+		return
+	}
+	return value, false, nil
+}
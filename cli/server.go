@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"errors"
+	"time"
+)
+
+// ServerOptions turns the app into a daemon, with the builtin
+// command group:
+//
+//	app server start [--foreground]
+//	app server stop [--force]
+//	app server restart
+//	app server status
+//	app server reload
+//	app generate systemd
+//
+// See cmdr.WithServer().
+type ServerOptions struct {
+	// Serve is the main loop of the daemon. It should return
+	// once ctx is canceled by SIGINT or SIGTERM.
+	Serve OnInvokeHandler
+
+	PidFile     string        // the pidfile, default is VarRunDir/<app>.pid
+	LogFile     string        // the output of background daemon, default is VarLogDir/<app>.log
+	StopTimeout time.Duration // how long `server stop` waits for exiting, default is 10s
+
+	// for the systemd unit generated by `generate systemd`
+
+	Description string   // default is the description of app
+	User        string   // run as this user
+	Group       string   // run as this group
+	After       []string // default is network.target
+	Environment []string // KEY=VALUE
+}
+
+var (
+	// ErrNotRunning means the daemon isn't running, it's returned
+	// by `server status`, and the exit code is 3 as LSB defines.
+	ErrNotRunning = errors.New("the daemon is not running")
+	// ErrAlreadyRunning is returned by `server start` if the pid
+	// in pidfile is still alive.
+	ErrAlreadyRunning = errors.New("the daemon is already running")
+)
+
+// ExitNotRunning is the exit code of ErrNotRunning, see LSB
+// init script actions.
+const ExitNotRunning = 3
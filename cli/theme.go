@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hedzr/is/term/color"
+)
+
+// Theme is a set of colors, by roles, for the help and debug
+// screens.
+//
+// Pick a builtin theme or your own one by cmdr.WithTheme():
+//
+//	app := cmdr.New(cmdr.WithTheme(cli.ThemeLight))
+//
+// The end user can override it in config file:
+//
+//	app:
+//	  theme:
+//	    name: high-contrast   # pick a builtin theme
+//	    desc: blue            # and override a role
+//	    group-title-bg: bold
+//
+// See ThemeRoles for the role names, and ParseColor for the
+// color names.
+type Theme struct {
+	Name         string      `json:"name,omitempty"`
+	Title        color.Color `json:"title,omitempty"`          // the title of a command
+	FlagTitle    color.Color `json:"flag-title,omitempty"`     // the title of a flag
+	Hidden       color.Color `json:"hidden,omitempty"`         // the left part of a hidden command or flag
+	Deprecated   color.Color `json:"deprecated,omitempty"`     // the deprecated command or flag
+	EnvVars      color.Color `json:"env-vars,omitempty"`       // the envvars of a flag
+	Desc         color.Color `json:"desc,omitempty"`           // the description
+	DefaultValue color.Color `json:"default-value,omitempty"`  // the default value of a flag
+	GroupTitle   color.Color `json:"group-title,omitempty"`    // the title of a group
+	GroupTitleBg color.Color `json:"group-title-bg,omitempty"` // the background of a group title
+}
+
+var (
+	// ThemeDefault is for the dark background terminals.
+	ThemeDefault = &Theme{
+		Name:         "default",
+		Title:        color.FgDefault,
+		FlagTitle:    color.FgGreen,
+		Hidden:       color.FgDarkGray,
+		Deprecated:   color.FgDarkGray,
+		EnvVars:      color.FgLightGray,
+		Desc:         color.FgDarkGray,
+		DefaultValue: color.FgCyan,
+		GroupTitle:   color.FgWhite,
+		GroupTitleBg: color.BgDim,
+	}
+
+	// ThemeLight is for the light background terminals.
+	ThemeLight = &Theme{
+		Name:         "light",
+		Title:        color.FgDefault,
+		FlagTitle:    color.FgBlue,
+		Hidden:       color.FgDarkGray,
+		Deprecated:   color.FgRed,
+		EnvVars:      color.FgMagenta,
+		Desc:         color.FgBlack,
+		DefaultValue: color.FgBlue,
+		GroupTitle:   color.FgBlack,
+		GroupTitleBg: color.BgBoldOrBright,
+	}
+
+	// ThemeHighContrast uses the bright colors only.
+	ThemeHighContrast = &Theme{
+		Name:         "high-contrast",
+		Title:        color.FgWhite,
+		FlagTitle:    color.FgLightGreen,
+		Hidden:       color.FgLightGray,
+		Deprecated:   color.FgLightRed,
+		EnvVars:      color.FgLightYellow,
+		Desc:         color.FgWhite,
+		DefaultValue: color.FgLightCyan,
+		GroupTitle:   color.FgLightYellow,
+		GroupTitleBg: color.BgBoldOrBright,
+	}
+
+	// ThemeMonochrome uses the default foreground color only.
+	ThemeMonochrome = &Theme{
+		Name:         "monochrome",
+		Title:        color.FgDefault,
+		FlagTitle:    color.FgDefault,
+		Hidden:       color.FgDefault,
+		Deprecated:   color.FgDefault,
+		EnvVars:      color.FgDefault,
+		Desc:         color.FgDefault,
+		DefaultValue: color.FgDefault,
+		GroupTitle:   color.FgDefault,
+		GroupTitleBg: color.BgNormal,
+	}
+
+	// Themes are the builtin themes by name.
+	Themes = map[string]*Theme{
+		"default":          ThemeDefault,
+		"light":            ThemeLight,
+		"light-background": ThemeLight,
+		"high-contrast":    ThemeHighContrast,
+		"monochrome":       ThemeMonochrome,
+		"mono":             ThemeMonochrome,
+	}
+)
+
+// ThemeRoles are the role names of a Theme, which are used as
+// the keys in config file, such as `app.theme.desc`.
+var ThemeRoles = []string{
+	"title", "flag-title", "hidden", "deprecated", "env-vars",
+	"desc", "default-value", "group-title", "group-title-bg",
+}
+
+// ThemeByName returns a builtin theme, or nil if not found.
+func ThemeByName(name string) *Theme {
+	return Themes[strings.ToLower(name)]
+}
+
+// Role returns the pointer to the color of a role, or nil if
+// the role is unknown.
+func (t *Theme) Role(role string) *color.Color {
+	switch strings.ToLower(role) {
+	case "title":
+		return &t.Title
+	case "flag-title":
+		return &t.FlagTitle
+	case "hidden":
+		return &t.Hidden
+	case "deprecated":
+		return &t.Deprecated
+	case "env-vars", "envvars":
+		return &t.EnvVars
+	case "desc", "description":
+		return &t.Desc
+	case "default-value", "default":
+		return &t.DefaultValue
+	case "group-title":
+		return &t.GroupTitle
+	case "group-title-bg":
+		return &t.GroupTitleBg
+	}
+	return nil
+}
+
+// Clone returns a copy of the theme.
+func (t *Theme) Clone() *Theme {
+	c := *t
+	return &c
+}
+
+var colorNames = map[string]color.Color{
+	"default":       color.FgDefault,
+	"black":         color.FgBlack,
+	"red":           color.FgRed,
+	"green":         color.FgGreen,
+	"yellow":        color.FgYellow,
+	"blue":          color.FgBlue,
+	"magenta":       color.FgMagenta,
+	"cyan":          color.FgCyan,
+	"light-gray":    color.FgLightGray,
+	"dark-gray":     color.FgDarkGray,
+	"light-red":     color.FgLightRed,
+	"light-green":   color.FgLightGreen,
+	"light-yellow":  color.FgLightYellow,
+	"light-blue":    color.FgLightBlue,
+	"light-magenta": color.FgLightMagenta,
+	"light-cyan":    color.FgLightCyan,
+	"white":         color.FgWhite,
+
+	// for group-title-bg
+	"normal":    color.BgNormal,
+	"bold":      color.BgBoldOrBright,
+	"dim":       color.BgDim,
+	"italic":    color.BgItalic,
+	"underline": color.BgUnderline,
+	"inverse":   color.BgInverse,
+}
+
+// ParseColor parses a color name, such as "dark-gray",
+// "light-blue" and "bold", or an ANSI code like "36".
+func ParseColor(name string) (clr color.Color, ok bool) {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+	if clr, ok = colorNames[name]; ok {
+		return
+	}
+	if clr, ok = colorNames[strings.ReplaceAll(name, "grey", "gray")]; ok {
+		return
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= 0 {
+		clr, ok = color.Color(n), true
+	}
+	return
+}
@@ -4,6 +4,8 @@ package cli
 
 import (
 	"context"
+	"os"
+	"time"
 
 	"github.com/hedzr/is/term/color"
 	"github.com/hedzr/store"
@@ -32,6 +34,9 @@ const (
 	// ExternalToolPasswordInput enables secure password input without echo.
 	ExternalToolPasswordInput = "PASSWD"
 
+	// SecretMask is used for displaying the value of a secret flag.
+	SecretMask = "******"
+
 	CtxKeyHelpScreenWriter = "cmdr.helpScreenWriter" // context key for help screen writer, for internal testing purpose only
 )
 
@@ -160,6 +165,19 @@ type BaseOpt struct {
 	hidden       bool
 	vendorHidden bool
 
+	// notes are the extra tips shown in Notes section of help
+	// screen and manpage.
+	notes []string
+	// seeAlso are the dotted paths of the related commands, such
+	// as "server.start".
+	seeAlso []string
+	// since is a version string just like 'v1.4', that means this
+	// command/flag was added in `v1.4`.
+	//
+	// It will be hidden if `--version-sim` specifies an older
+	// version.
+	since string
+
 	// hitTitle keeps the matched title string from user input in command line
 	hitTitle string
 	// hitTimes how many times this flag was triggered.
@@ -326,8 +344,47 @@ type CmdPriv interface {
 	findFlagBackwardsIn(ctx context.Context, cc Cmd, children []Cmd, longName string) (res *Flag)
 }
 
+// CmdMetadata is an optional interface of Cmd for the extra
+// metadata shown in help screen and manpages. A Cmd which
+// doesn't implement it has no such metadata.
+//
+// See also NotesOf, SeeAlsoOf and SinceOf.
+type CmdMetadata interface {
+	Notes() []string   // the extra notes
+	SeeAlso() []string // the dotted paths of the related commands
+	Since() string     // the version which this command was added in
+}
+
+// NotesOf returns the extra notes of cc, if it implements
+// CmdMetadata.
+func NotesOf(cc Cmd) []string {
+	if x, ok := cc.(CmdMetadata); ok {
+		return x.Notes()
+	}
+	return nil
+}
+
+// SeeAlsoOf returns the dotted paths of the related commands of
+// cc, if it implements CmdMetadata.
+func SeeAlsoOf(cc Cmd) []string {
+	if x, ok := cc.(CmdMetadata); ok {
+		return x.SeeAlso()
+	}
+	return nil
+}
+
+// SinceOf returns the version which cc was added in, if it
+// implements CmdMetadata.
+func SinceOf(cc Cmd) string {
+	if x, ok := cc.(CmdMetadata); ok {
+		return x.Since()
+	}
+	return ""
+}
+
 var _ Cmd = (*CmdS)(nil)
 var _ CmdPriv = (*CmdS)(nil)
+var _ CmdMetadata = (*CmdS)(nil)
 
 // CmdS is the official Command implementation of a Cmd interface.
 type CmdS struct {
@@ -363,6 +420,14 @@ type CmdS struct {
 	onInvoke OnInvokeHandler
 	// postActions will be launched after running OnInvoke.
 	postActions []OnPostInvokeHandler
+	// middlewares wrap the action of this command and its
+	// subcommands, see Use.
+	middlewares []Middleware
+	// timeout gives the action a deadline, see SetTimeout.
+	timeout time.Duration
+	// confirm is the question asked before invoking the action,
+	// see SetConfirm.
+	confirm string
 
 	onMatched []OnCommandMatchedHandler
 
@@ -458,6 +523,7 @@ type Flag struct {
 	min, max       int
 	headLike       bool
 	required       bool
+	secret         bool // sensitive value, masked in outputs and never written back
 
 	// a pointer for the associated var to receive this flag's
 	// final value after parsed.
@@ -597,6 +663,10 @@ type OnSingleHyphenHandler func(ctx context.Context, state ParsedState) (err err
 
 type OnUnknownCommandHandler func(ctx context.Context, title string, cmd Cmd, errUnmatched error) (err error)
 
+// OnSignalHandler handles an os signal while the app is running,
+// such as SIGHUP for reloading the configurations.
+type OnSignalHandler func(ctx context.Context, sig os.Signal) (err error)
+
 // OnChangingHandler handles when a flag has been setting by parsing command-line
 // args, loading from external sources and other cases.
 //
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/hedzr/is/states"
 	logzorig "github.com/hedzr/logg/slog"
@@ -17,8 +18,10 @@ func (w *workerS) addBuiltinCommands(root *cli.RootCommand) (err error) { //noli
 	app, rcmd := root.App(), root.Cmd
 	if cmd, ok := rcmd.(*cli.CmdS); ok {
 		w.builtinCmdrs(app, cmd)
+		w.builtinOutputs(app, cmd) // before the builtin commands, see builtinTitles
 		w.builtinSBOM(app, cmd)
 		w.builtinGenerators(app, cmd)
+		w.builtinServer(app, cmd)
 		w.builtinVerboses(app, cmd)
 		w.builtinVersions(app, cmd)
 		w.builtinHelps(app, cmd)
@@ -92,6 +95,23 @@ func (w *workerS) builtinHelps(app cli.App, p *cli.CmdS) {
 				err = w.helpSystemAction(ctx, cmd, args)
 				// w.actionsMatched |= actionShowHelpScreen
 				return // return cli.ErrShouldStop
+			}).
+			With(func(b cli.CommandBuilder) {
+				b.Flg("search", "s", "find").
+					Default("").
+					Description("Search the commands, flags and help text").
+					PlaceHolder("TERMS").
+					Examples(`
+$ {{.AppName}} help --search "data center"
+	list the commands and flags about 'data center', ranked by relevance
+`).
+					OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+						if v, ok := hitState.Value.(string); ok {
+							w.searchTerms = v
+						}
+						return
+					}).
+					Build()
 			})
 	})
 
@@ -160,6 +180,62 @@ func (w *workerS) builtinHelps(app cli.App, p *cli.CmdS) {
 			Default(false).
 			DoubleTildeOnly(true)
 	})
+	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+		b.Titles("no-pager").
+			Description("Don't pipe the long help screen through pager ($PAGER or 'less -R')").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, true).
+			Examples(`
+$ {{.AppName}} ~~tree --no-pager
+	print the whole tree to stdout directly
+$ APP_PAGER='more' {{.AppName}} --help
+	use 'more' as the pager, and an empty 'APP_PAGER=' disables paging
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				if v, ok := hitState.Value.(bool); ok {
+					w.noPager = v
+				}
+				return
+			})
+	})
+	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+		b.Titles("json").
+			Description("Export the command tree in JSON format, same as '--format=json'").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, true).
+			Examples(`
+$ {{.AppName}} ~~tree --json
+	export the whole command tree, with the flags of each command
+$ {{.AppName}} server --help --format=yaml
+	export the subtree of 'server' command in YAML format
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				if v, ok := hitState.Value.(bool); ok && v {
+					w.format = "json"
+				}
+				return
+			}).
+			CompPrerequisites("tree")
+	})
+	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+		b.Titles("with-flags").
+			Description("Include the flags in the diagram of '~~tree --format=dot|mermaid|plantuml'").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, true).
+			Examples(`
+$ {{.AppName}} ~~tree --format=dot | dot -Tsvg -o commands.svg
+	draw the command hierarchy with Graphviz
+$ {{.AppName}} ~~tree --format=mermaid --with-flags > commands.mmd
+	export a Mermaid flowchart of commands and their flags
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				if v, ok := hitState.Value.(bool); ok {
+					w.treeWithFlags = v
+				}
+				return
+			}).
+			CompPrerequisites("tree")
+	})
 
 	// find config file loader at first
 	found := false
@@ -195,6 +271,153 @@ $ {{.AppName}} --config=ci/etc/demo-yy/any.yml ~~debug
 				EnvVars("CONFIG", "CONF_FILE")
 		})
 	}
+
+	app.NewFlgFrom(p, "", func(b cli.FlagBuilder) {
+		b.Titles("profile").
+			Description("Activate a config profile (overlay 'profiles.NAME.*')").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, false).
+			PlaceHolder("NAME").
+			Examples(`
+$ {{.AppName}} --profile prod ~~debug
+	overlay the entries under 'profiles.prod' onto the config store
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				// the profile has been applied in preProcess, here we
+				// just validate and keep it.
+				var ok bool
+				w.profile, ok = hitState.Value.(string)
+				if !ok {
+					err = fmt.Errorf("value is not a string. [value=%v]", hitState.Value)
+				}
+				return
+			}).
+			EnvVars(w.profileEnvVar())
+	})
+
+	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+		b.Titles("save-config").
+			Description("Save the flags given on command-line into user config file").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, false).
+			Examples(`
+$ {{.AppName}} server start --port 8080 --save-config
+	remember '--port 8080' in '$CONFIG_DIR/{{.AppName}}.json' (or the json file given by '--config')
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				if v, ok := hitState.Value.(bool); ok {
+					w.saveConfig = v
+				}
+				return
+			})
+	})
+
+	app.NewFlgFrom(p, "", func(b cli.FlagBuilder) {
+		b.Titles("lang", "", "locale").
+			Description("The language of help screen and messages, such as en, zh-CN, de").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, false).
+			PlaceHolder("LOCALE").
+			Examples(`
+$ {{.AppName}} --lang zh-CN --help
+	show the help screen in Chinese, the default locale comes from $LC_ALL or $LANG
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				var ok bool
+				w.locale, ok = hitState.Value.(string)
+				if !ok {
+					err = fmt.Errorf("value is not a string. [value=%v]", hitState.Value)
+					return
+				}
+				cli.SetLocale(w.locale)
+				return
+			})
+	})
+
+	app.NewFlgFrom(p, time.Duration(0), func(b cli.FlagBuilder) {
+		b.Titles("timeout", "").
+			Description("Give the action a deadline, such as 30s, 5m").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, false).
+			PlaceHolder("DURATION").
+			Examples(`
+$ {{.AppName}} --timeout 30s server start
+	abort the action if it doesn't finish in 30 seconds, the exit code is 124
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				var ok bool
+				w.timeout, ok = hitState.Value.(time.Duration)
+				if !ok {
+					err = fmt.Errorf("value is not a duration. [value=%v]", hitState.Value)
+				}
+				return
+			})
+	})
+
+	app.NewFlgFrom(p, "", func(b cli.FlagBuilder) {
+		b.Titles("error-format", "").
+			Description("Print the error as <code>text</code> or <code>json</code>, for scripts").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, false).
+			PlaceHolder("FORMAT").
+			ValidArgs("text", "json").
+			Examples(`
+$ {{.AppName}} --error-format=json server start
+	print {"error":"...","code":64,"kind":"usage",...} to stderr if it failed
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				var ok bool
+				w.errorFormat, ok = hitState.Value.(string)
+				if !ok {
+					err = fmt.Errorf("value is not a string. [value=%v]", hitState.Value)
+				}
+				return
+			})
+	})
+
+	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+		b.Titles("no-input", "").
+			Description("Never prompt, fail with the error if a required flag or a secret is missing").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, false).
+			EnvVars("NO_INPUT").
+			Examples(`
+$ {{.AppName}} server start --no-input
+	for scripts and CI, even if the stdin is a terminal
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				if v, ok := hitState.Value.(bool); ok {
+					w.noInput = v
+				}
+				return
+			})
+	})
+
+	// the flags of app take precedence over '--yes' and '--dry-run',
+	// and a command's own one shadows the builtin one for itself
+	// and its subcommands.
+	if short, ok := builtinTitles(p, "yes", "y"); ok {
+		app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+			b.Titles("yes", short, "assume-yes").
+				Description("Answer yes to the confirmations of the destructive commands").
+				Group(cli.SysMgmtGroup).
+				Hidden(true, false).
+				EnvVars("ASSUME_YES")
+		})
+	}
+	if short, ok := builtinTitles(p, "dry-run", "n"); ok {
+		app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+			b.Titles("dry-run", short).
+				Description("Print what would be done instead of doing it, see ParsedState.DryRun()").
+				Group(cli.SysMgmtGroup).
+				Hidden(true, false).
+				EnvVars("DRY_RUN").
+				Examples(`
+$ {{.AppName}} server stop --dry-run
+	print what would be done instead of stopping the daemon
+`)
+		})
+	}
 }
 
 func (w *workerS) builtinVerboses(app cli.App, p *cli.CmdS) {
@@ -265,19 +488,74 @@ func (w *workerS) builtinVerboses(app cli.App, p *cli.CmdS) {
 
 	mutualExclusives := []string{"raw", "value-type", "more", "env"}
 
+	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+		b.Titles("health").
+			Description("Probe the health of peripherals with '~~health'").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, true).
+			Examples(`
+$ {{.AppName}} ~~health
+	open the peripherals and probe them, the exit code is 69 if any one is unhealthy
+$ {{.AppName}} ~~health --format=json
+	print the results in json
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				if hitState.DblTilde {
+					w.actionsMatched |= cli.ActionShowHealth // ~~health to probe peripherals
+				}
+				return
+			})
+	})
+
 	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
 		b.Titles("env").
-			Description("Dump environment info in '~~debug' mode").
+			Description("Dump environment info in '~~debug' mode, or alone with '~~env'").
 			Group(cli.SysMgmtGroup).
 			Hidden(true, true).
 			// EnvVars("ENV").
 			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
-				w.actionsMatched |= cli.ActionShowDebugEnv
+				if hitState.DblTilde {
+					w.actionsMatched |= cli.ActionShowDebugEnv // ~~env to show env screen
+				}
 				return
 			}).
 			CompPrerequisites("debug").
 			CompMutualExclusives(mutualExclusives...)
 	})
+	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
+		b.Titles("all").
+			Description("List all envvars recognized by this app with '~~env'").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, true).
+			Examples(`
+$ {{.AppName}} ~~env --all
+	list the envvars with their types, defaults, current values and bound flags
+$ {{.AppName}} ~~env --all --format=export
+	print them as shell 'export' lines
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				if v, ok := hitState.Value.(bool); ok {
+					w.envAll = v
+				}
+				return
+			}).
+			CompPrerequisites("env")
+	})
+	app.NewFlgFrom(p, "", func(b cli.FlagBuilder) {
+		b.Titles("format").
+			Description("The output format of builtin screens, such as '~~env --all', '--help' and '~~tree' (json, yaml, dot, mermaid, plantuml)").
+			Group(cli.SysMgmtGroup).
+			Hidden(true, true).
+			PlaceHolder("FORMAT").
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				var ok bool
+				w.format, ok = hitState.Value.(string)
+				if !ok {
+					err = fmt.Errorf("value is not a string. [value=%v]", hitState.Value)
+				}
+				return
+			})
+	})
 	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
 		b.Titles("more").
 			Description("Dump more info in '~~debug' mode").
@@ -319,6 +597,37 @@ func (w *workerS) builtinVerboses(app cli.App, p *cli.CmdS) {
 	})
 }
 
+// builtinTitles tests if the long title of a builtin flag is not
+// taken by the flags of p, and returns the short title if it's
+// not taken by any flag in the whole tree of p.
+func builtinTitles(p *cli.CmdS, long, short string) (shortFree string, ok bool) {
+	for _, ff := range p.Flags() {
+		if ff.Long == long {
+			return "", false
+		}
+	}
+	if shortTaken(p, short) {
+		return "", true
+	}
+	return short, true
+}
+
+// shortTaken tests if short is the short title of a flag of p or
+// its subcommands.
+func shortTaken(p *cli.CmdS, short string) bool {
+	for _, ff := range p.Flags() {
+		if ff.Short == short {
+			return true
+		}
+	}
+	for _, cc := range p.SubCommands() {
+		if shortTaken(cc, short) {
+			return true
+		}
+	}
+	return false
+}
+
 func (w *workerS) builtinCmdrs(app cli.App, p *cli.CmdS) {
 	app.NewFlgFrom(p, false, func(b cli.FlagBuilder) {
 		b.Titles("strict-mode", "").
@@ -506,6 +815,21 @@ $ {{.AppName}} gen man
 					ToggleGroup("Shell").
 					Build()
 			})
+
+		if w.hasBuiltinServer(p) {
+			bb.Cmd("systemd", "", "unit").
+				Description("Generate the systemd unit file of the daemon").
+				Group(cli.SysMgmtGroup).
+				OnAction(w.genSystemd).
+				With(func(b cli.CommandBuilder) {
+					b.Flg("output", "o").
+						Default("").
+						Description("The output filename, default is stdout").
+						Group("Output").
+						PlaceHolder("FILE").
+						Build()
+				})
+		}
 	})
 }
 
@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// compDirectiveNoFileComp is the directive for the completion
+// scripts of fish and powershell, which asks the shell not to
+// complete the file names.
+const compDirectiveNoFileComp = 4
+
+// isCompleteRequest tests if cmd is invoked as `__complete`, the
+// completion request from the scripts of fish and powershell.
+func isCompleteRequest(cmd cli.Cmd) bool {
+	switch cmd.HitTitle() {
+	case "__complete", "__completion":
+		return true
+	}
+	return false
+}
+
+// completeArgs answers `app __complete words... partial`: it
+// prints the candidates for the last (partial) word, one per
+// line, and the directive line `:4` at last.
+//
+// The words before partial are the subcommands. After `help`,
+// the words are the command path from root, and the names of
+// help topics are the candidates too.
+//
+// The words are taken from the raw command-line, since the
+// parser drops the empty partial word, which means completing a
+// new word.
+func (w *workerS) completeArgs(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	if i := slices.Index(w.Args(), cmd.HitTitle()); i >= 0 {
+		args = w.Args()[i+1:]
+	}
+
+	var sb strings.Builder
+	for _, c := range w.completeCandidates(ctx, cmd.Root().Cmd, args) {
+		_, _ = sb.WriteString(c)
+		_, _ = sb.WriteString("\n")
+	}
+	_, _ = sb.WriteString(fmt.Sprintf(":%d\n", compDirectiveNoFileComp))
+	_, err = (&helpPrinter{w: w}).safeGetWriter().WriteString(sb.String())
+	return
+}
+
+func (w *workerS) completeCandidates(ctx context.Context, root cli.Cmd, args []string) (candidates []string) {
+	var partial string
+	if len(args) > 0 {
+		args, partial = args[:len(args)-1], args[len(args)-1]
+	}
+
+	cc, inHelp := root, false
+	for _, word := range args {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		if cc = cc.FindSubCommand(ctx, word, true); cc == nil {
+			return
+		}
+		if isHelpCommand(cc) && !inHelp {
+			cc, inHelp = root, true
+		}
+	}
+
+	if strings.HasPrefix(partial, "-") {
+		for c := cc; ; c = c.OwnerCmd() {
+			for _, ff := range c.Flags() {
+				if ff.Hidden() || ff.VendorHidden() {
+					continue
+				}
+				for _, t := range ff.GetTitleZshFlagNamesArray() {
+					if strings.HasPrefix(t, partial) {
+						candidates = append(candidates, t)
+					}
+				}
+			}
+			if c.OwnerIsNil() {
+				break
+			}
+		}
+	} else {
+		for _, sc := range cc.SubCommands() {
+			if sc.Hidden() || sc.VendorHidden() {
+				continue
+			}
+			if n := sc.Name(); strings.HasPrefix(n, partial) {
+				candidates = append(candidates, n)
+			}
+		}
+		if inHelp && cc == root {
+			for _, t := range w.helpTopics() {
+				for _, n := range t.Titles() {
+					if strings.HasPrefix(n, partial) {
+						candidates = append(candidates, n)
+					}
+				}
+			}
+		}
+	}
+	slices.Sort(candidates)
+	return slices.Compact(candidates)
+}
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// confirmCmd asks the user to confirm the question of cmd (see
+// cli.CmdS.SetConfirm) before invoking it. It's answered by the
+// builtin flag '--yes', and skipped in '--dry-run' mode.
+func (w *workerS) confirmCmd(ctx context.Context, pc *parseCtx, cmd cli.Cmd) (err error) {
+	cc, ok := cmd.(*cli.CmdS)
+	if !ok || cc.Confirm() == "" || pc.DryRun() || boolFlagSet(ctx, cmd, "yes") {
+		return
+	}
+
+	question := confirmQuestion(cc.Confirm(), len(pc.positionalArgs))
+	p := w.getPrompter()
+	if w.noInput || !p.IsTerminal() {
+		return cli.LocalizeError(cli.MsgErrConfirmRequired, cli.ErrConfirmRequired, cmd.GetDottedPath())
+	}
+
+	answer, _ := p.ReadLine(cli.Tf(cli.MsgPromptConfirm, strings.TrimRight(question, ".!? ")))
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		logz.VerboseContext(ctx, "command confirmed", "cmd", cmd)
+		return
+	}
+	return cli.LocalizeError(cli.MsgErrNotConfirmed, cli.ErrNotConfirmed, cmd.GetDottedPath())
+}
+
+// confirmQuestion replaces "%d" in question with the count of
+// positional args, and "%%" with '%'. Other '%' are literal, so
+// a question like "Wipe 100% of the disk" is safe.
+func confirmQuestion(question string, count int) string {
+	if !strings.Contains(question, "%") {
+		return question
+	}
+	var sb strings.Builder
+	for i := 0; i < len(question); i++ {
+		if question[i] == '%' && i+1 < len(question) {
+			switch question[i+1] {
+			case 'd':
+				_, _ = sb.WriteString(strconv.Itoa(count))
+				i++
+				continue
+			case '%':
+				_ = sb.WriteByte('%')
+				i++
+				continue
+			}
+		}
+		_ = sb.WriteByte(question[i])
+	}
+	return sb.String()
+}
+
+// dryRun reports whether '--dry-run' is set for the running
+// command.
+func (w *workerS) dryRun() bool {
+	return w.parsingCtx != nil && w.parsingCtx.DryRun()
+}
+
+// printDryRun prints the command line, or what would be done, in
+// '--dry-run' mode.
+func (w *workerS) printDryRun(line string) (err error) {
+	_, err = fmt.Fprintln((&helpPrinter{w: w}).safeGetWriter(), cli.Tf(cli.MsgDryRun, strings.TrimSpace(line)))
+	return
+}
+
+// boolFlagSet tests the bool flag of cmd or its parents, which
+// may be set by the command line or its envvars.
+func boolFlagSet(ctx context.Context, cmd cli.Cmd, longTitle string) bool {
+	if cmd == nil {
+		return false
+	}
+	if ff := cmd.FindFlagBackwards(ctx, longTitle); ff != nil {
+		v, _ := ff.DefaultValue().(bool)
+		return v
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_Confirm(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(p *fakePrompter, args ...string) (invoked, dryRun bool, out string, err error) {
+		ta := newTestApp(t, ctx)
+		ta.ww.prompter = p
+		ta.consul.SetConfirm("This will delete %d resources.")
+		ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			invoked, dryRun = true, ta.ww.ParsedState().DryRun()
+			return
+		})
+		err = ta.run(ctx, append([]string{"consul"}, args...)...)
+		return invoked, dryRun, ta.sb.String(), err
+	}
+
+	p := &fakePrompter{tty: true, answers: []string{"y"}}
+	if invoked, _, _, err := run(p, "a", "b"); err != nil || !invoked {
+		t.Fatalf("expecting invoked after confirming, got %v, %v", invoked, err)
+	}
+	if got := p.out.String(); got != "This will delete 2 resources. Continue? [y/N]: " {
+		t.Fatalf("unexpected question: %q", got)
+	}
+
+	if invoked, _, _, err := run(&fakePrompter{tty: true, answers: []string{"n"}}); !errors.Is(err, cli.ErrNotConfirmed) || invoked {
+		t.Fatalf("expecting ErrNotConfirmed, got %v, %v", invoked, err)
+	}
+	if _, _, _, err := run(&fakePrompter{tty: false}); !errors.Is(err, cli.ErrConfirmRequired) || cli.ExitCodeOf(err) != cli.ExitUsage {
+		t.Fatalf("expecting ErrConfirmRequired in non-interactive mode, got %v", err)
+	}
+	if _, _, _, err := run(&fakePrompter{tty: true, answers: []string{"y"}}, "--no-input"); !errors.Is(err, cli.ErrConfirmRequired) {
+		t.Fatalf("expecting ErrConfirmRequired with --no-input, got %v", err)
+	}
+
+	for _, args := range [][]string{{"--yes"}, {"-y"}} {
+		if invoked, _, _, err := run(&fakePrompter{tty: false}, args...); err != nil || !invoked {
+			t.Fatalf("%v: expecting invoked without asking, got %v, %v", args, invoked, err)
+		}
+	}
+	t.Setenv("ASSUME_YES", "true")
+	if invoked, _, _, err := run(&fakePrompter{tty: false}); err != nil || !invoked {
+		t.Fatalf("expecting invoked by $ASSUME_YES, got %v, %v", invoked, err)
+	}
+}
+
+func TestConfirmQuestion(t *testing.T) {
+	for question, expect := range map[string]string{
+		"This will delete %d resources": "This will delete 3 resources",
+		"Wipe 100% of the disk":         "Wipe 100% of the disk",
+		"Wipe 100%% of %d disks":        "Wipe 100% of 3 disks",
+		"Delete %s and %v":              "Delete %s and %v",
+		"Trailing %":                    "Trailing %",
+		"No verb":                       "No verb",
+	} {
+		if got := confirmQuestion(question, 3); got != expect {
+			t.Fatalf("%q: expecting %q, got %q", question, expect, got)
+		}
+	}
+}
+
+func TestWorkerS_DryRun(t *testing.T) {
+	ctx := context.Background()
+
+	ta := newTestApp(t, ctx)
+	ta.ww.prompter = &fakePrompter{tty: false}
+	ta.consul.SetConfirm("This will wipe the disk")
+	ta.consul.SetInvokeProc("rm -rf /nonexistent/cmdr-dry-run")
+
+	// the demo app has its own '--dry-run', which takes precedence
+	if err := ta.run(ctx, "consul", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if !ta.ww.ParsedState().DryRun() || ta.sb.String() != "[dry-run] rm -rf /nonexistent/cmdr-dry-run\n" {
+		t.Fatalf("expecting the command line printed, got %q", ta.sb.String())
+	}
+}
+
+func TestWorkerS_BuiltinDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	// run runs the demo app without its own '--dry-run', so the
+	// builtin one is used. The command 'micro-service' of the demo app
+	// takes the short title '-n' if it's kept.
+	run := func(keepMicroService bool, args ...string) (ta *testApp, err error) {
+		ta = newTestApp(t, ctx)
+		ta.removeFlag("dry-run")
+		if !keepMicroService {
+			ta.removeCommand("micro-service")
+		}
+		ta.ww.prompter = &fakePrompter{tty: false}
+		ta.consul.SetConfirm("This will wipe the disk")
+		ta.consul.SetInvokeProc("rm -rf /nonexistent/cmdr-dry-run")
+		err = ta.run(ctx, append([]string{"consul"}, args...)...)
+		return
+	}
+
+	for _, args := range [][]string{{"--dry-run"}, {"-n"}} {
+		ta, err := run(false, args...)
+		if err != nil || ta.sb.String() != "[dry-run] rm -rf /nonexistent/cmdr-dry-run\n" {
+			t.Fatalf("%v: expecting the command line printed, got %q, %v", args, ta.sb.String(), err)
+		}
+	}
+
+	ta, err := run(true, "--dry-run")
+	if err != nil || !ta.ww.ParsedState().DryRun() {
+		t.Fatalf("expecting the builtin '--dry-run', got %v", err)
+	}
+	if ff := ta.ww.root.Cmd.(*cli.CmdS).FindFlag(ctx, "dry-run", false); ff == nil || ff.Short != "" {
+		t.Fatal("expecting the builtin '--dry-run' without the short title taken by 'micro-service'")
+	}
+
+	t.Setenv("DRY_RUN", "1")
+	if ta, err = run(false); err != nil || !ta.ww.ParsedState().DryRun() {
+		t.Fatalf("expecting dry-run mode by $DRY_RUN, got %q, %v", ta.sb.String(), err)
+	}
+}
@@ -0,0 +1,339 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hedzr/is/dir"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// dotenvEntry is a variable loaded from a dotenv file, with
+// its provenance.
+type dotenvEntry struct {
+	Key   string
+	Value string
+	File  string
+	Line  int
+}
+
+func (e dotenvEntry) Source() string { return fmt.Sprintf("%s:%d", e.File, e.Line) }
+
+// maskedDotEnvs returns the loaded dotenv entries for the debug
+// screen. A dotenv file often holds the api keys and tokens, so
+// the values are masked unless they are bound to the flags which
+// are not secret.
+func (w *workerS) maskedDotEnvs(ctx context.Context) (entries []dotenvEntry) {
+	if len(w.dotenvs) == 0 {
+		return
+	}
+	plain := make(map[string]bool)
+	if cx, ok := w.root.Cmd.(*cli.CmdS); ok {
+		cx.WalkEverything(ctx, func(cc, pp cli.Cmd, ff *cli.Flag, cmdIndex, flgIndex, level int) {
+			if ff == nil || ff.Secret() {
+				return
+			}
+			_, _, _, _, _ = cc, pp, cmdIndex, flgIndex, level
+			for _, ev := range ff.EnvVars() {
+				plain[ev] = true
+			}
+			if w.AutoEnv {
+				plain[ff.GetAutoEnvVarName(w.AutoEnvPrefix, true)] = true
+			}
+		})
+	}
+	for _, e := range w.dotenvs {
+		if !plain[e.Key] && e.Value != "" {
+			e.Value = cli.SecretMask
+		}
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b dotenvEntry) int { return strings.Compare(a.Key, b.Key) })
+	return
+}
+
+// dotenvFiles returns the dotenv files in loading order, the
+// later one overrides the former.
+//
+// They are `.env`, `.env.local` and `.env.<profile>` in the
+// config dir, and then the ones in the working directory.
+func (w *workerS) dotenvFiles() (files []string) {
+	names := []string{".env", ".env.local"}
+	if w.profile != "" {
+		names = append(names, ".env."+w.profile)
+	}
+
+	var dirs []string
+	if configDir := os.Getenv("CONFIG_DIR"); configDir != "" {
+		dirs = append(dirs, configDir)
+	}
+	if cwd := dir.GetCurrentDir(); cwd != "" && (len(dirs) == 0 || dirs[0] != cwd) {
+		dirs = append(dirs, cwd)
+	}
+
+	for _, d := range dirs {
+		for _, name := range names {
+			files = append(files, filepath.Join(d, name))
+		}
+	}
+	return
+}
+
+// loadDotEnv loads the dotenv files into the process environment
+// before the envvars are bound to flags, if it's enabled by
+// [cli.Config.LoadDotEnv].
+//
+// The real environment always wins: a variable which has been
+// set before loading is never overwritten by dotenv files.
+func (w *workerS) loadDotEnv(ctx context.Context) {
+	if !w.LoadDotEnv {
+		return
+	}
+
+	realEnv := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			realEnv[k] = v
+		}
+	}
+	lookupReal := func(key string) (v string, ok bool) { v, ok = realEnv[key]; return }
+	lookupFormer := func(key string) (v string, ok bool) {
+		if e, has := w.dotenvs[key]; has {
+			return e.Value, true
+		}
+		return
+	}
+
+	for _, file := range w.dotenvFiles() {
+		f, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+		entries, err := parseDotEnv(f, file, lookupReal, lookupFormer)
+		_ = f.Close()
+		if err != nil {
+			logz.WarnContext(ctx, "[cmdr] cannot parse dotenv file", "file", file, "err", err)
+			continue
+		}
+
+		for _, e := range entries {
+			if _, ok := realEnv[e.Key]; ok {
+				logz.VerboseContext(ctx, "[cmdr] dotenv entry ignored, the real env wins", "key", e.Key, "source", e.Source())
+				continue
+			}
+			_ = os.Setenv(e.Key, e.Value)
+			if w.dotenvs == nil {
+				w.dotenvs = make(map[string]dotenvEntry)
+			}
+			w.dotenvs[e.Key] = e
+		}
+		logz.DebugContext(ctx, "[cmdr] dotenv file loaded", "file", file, "count", len(entries))
+	}
+}
+
+// parseDotEnv parses a dotenv file.
+//
+// The supported syntax:
+//
+//	# comment
+//	KEY=value                   # inline comment
+//	export KEY=value
+//	KEY="double quoted, with escapes \n and ${VAR:-default}"
+//	KEY='single quoted, literally'
+//	KEY="multi-line
+//	value"
+//
+// The interpolation forms `$VAR`, `${VAR}`, `${VAR:-default}`
+// and `${VAR-default}` are expanded in unquoted and double
+// quoted values. The variables are looked up in lookup (the real
+// environment) at first, then in the former entries of this file,
+// and then in former (the ones loaded from the former dotenv
+// files) if it's not nil.
+func parseDotEnv(r io.Reader, file string, lookup, former func(key string) (string, bool)) (entries []dotenvEntry, err error) {
+	local := make(map[string]string)
+	get := func(key string) (string, bool) {
+		if v, ok := lookup(key); ok {
+			return v, true
+		}
+		if v, ok := local[key]; ok {
+			return v, true
+		}
+		if former != nil {
+			return former(key)
+		}
+		return "", false
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		start := lineNo
+		line = strings.TrimPrefix(line, "export ")
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isDotEnvKey(key) {
+			return nil, fmt.Errorf("%s:%d: invalid line %q", file, lineNo, line)
+		}
+		rest = strings.TrimSpace(rest)
+
+		var value string
+		if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+			quote := rest[0]
+			body := rest[1:]
+			for {
+				if end := closingQuote(body, quote); end >= 0 {
+					body = body[:end]
+					break
+				}
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("%s:%d: unterminated quoted value of %q", file, start, key)
+				}
+				lineNo++
+				body += "\n" + scanner.Text()
+			}
+			if quote == '\'' {
+				value = body
+			} else {
+				value = expandDotEnv(unescapeDotEnv(body), get)
+			}
+		} else {
+			if pos := strings.Index(rest, " #"); pos >= 0 {
+				rest = strings.TrimSpace(rest[:pos])
+			}
+			value = expandDotEnv(rest, get)
+		}
+
+		local[key] = value
+		entries = append(entries, dotenvEntry{Key: key, Value: value, File: file, Line: start})
+	}
+	err = scanner.Err()
+	return
+}
+
+func isDotEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// closingQuote returns the position of the unescaped closing
+// quote, or -1 if not found.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeDotEnv(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				_ = sb.WriteByte('\n')
+			case 'r':
+				_ = sb.WriteByte('\r')
+			case 't':
+				_ = sb.WriteByte('\t')
+			case '$':
+				// keep the escaped '$' away from expanding
+				_, _ = sb.WriteString(`\$`)
+			default:
+				_ = sb.WriteByte(s[i])
+			}
+			continue
+		}
+		_ = sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// expandDotEnv expands `$VAR`, `${VAR}`, `${VAR:-default}` and
+// `${VAR-default}`. An escaped `\$` is kept as a literal '$'.
+func expandDotEnv(s string, get func(key string) (string, bool)) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && s[i+1] == '$' {
+			_ = sb.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' || i+1 >= len(s) {
+			_ = sb.WriteByte(c)
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				_ = sb.WriteByte(c)
+				continue
+			}
+			expr := s[i+2 : i+2+end]
+			i += 2 + end
+			name, def, hasDef, emptyAsUnset := expr, "", false, false
+			if k, d, ok := strings.Cut(expr, ":-"); ok {
+				name, def, hasDef, emptyAsUnset = k, d, true, true
+			} else if k, d, ok := strings.Cut(expr, "-"); ok {
+				name, def, hasDef = k, d, true
+			}
+			v, ok := get(name)
+			if hasDef && (!ok || (emptyAsUnset && v == "")) {
+				v = expandDotEnv(def, get)
+			}
+			_, _ = sb.WriteString(v)
+			continue
+		}
+
+		j := i + 1
+		for j < len(s) && (s[j] == '_' || s[j] >= 'A' && s[j] <= 'Z' || s[j] >= 'a' && s[j] <= 'z' || j > i+1 && s[j] >= '0' && s[j] <= '9') {
+			j++
+		}
+		if j == i+1 {
+			_ = sb.WriteByte(c)
+			continue
+		}
+		v, _ := get(s[i+1 : j])
+		_, _ = sb.WriteString(v)
+		i = j - 1
+	}
+	return sb.String()
+}
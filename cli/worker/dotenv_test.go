@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	src := `# comment
+PLAIN=hello world   # inline comment
+export EXPORTED=yes
+SINGLE='literal ${PLAIN}'
+DOUBLE="tab\there ${PLAIN}"
+REF=${HOME_OF_TEST}/bin
+DEF=${MISSED_OF_TEST:-fallback}
+EMPTY_DEF=${EMPTY_OF_TEST:-empty}
+DASH_DEF=${EMPTY_OF_TEST-dash}
+SHORT=$PLAIN!
+ESCAPED="\${PLAIN}"
+MULTI="line 1
+line 2"
+AFTER=after
+`
+	env := map[string]string{"HOME_OF_TEST": "/home/test", "EMPTY_OF_TEST": ""}
+	lookup := func(key string) (v string, ok bool) { v, ok = env[key]; return }
+
+	entries, err := parseDotEnv(strings.NewReader(src), ".env", lookup, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := make(map[string]dotenvEntry)
+	for _, e := range entries {
+		m[e.Key] = e
+	}
+	for i, c := range []struct {
+		key, value string
+		line       int
+	}{
+		{"PLAIN", "hello world", 2},
+		{"EXPORTED", "yes", 3},
+		{"SINGLE", "literal ${PLAIN}", 4},
+		{"DOUBLE", "tab\there hello world", 5},
+		{"REF", "/home/test/bin", 6},
+		{"DEF", "fallback", 7},
+		{"EMPTY_DEF", "empty", 8},
+		{"DASH_DEF", "", 9},
+		{"SHORT", "hello world!", 10},
+		{"ESCAPED", "${PLAIN}", 11},
+		{"MULTI", "line 1\nline 2", 12},
+		{"AFTER", "after", 14},
+	} {
+		e, ok := m[c.key]
+		if !ok || e.Value != c.value || e.Line != c.line {
+			t.Fatalf("%d. %s = %q (line %d); want %q (line %d)", i, c.key, e.Value, e.Line, c.value, c.line)
+		}
+	}
+
+	if _, err = parseDotEnv(strings.NewReader("BROKEN=\"no end\n"), ".env", lookup, nil); err == nil {
+		t.Fatal("expecting error for unterminated quoted value")
+	}
+}
+
+func TestWorkerS_LoadDotEnv(t *testing.T) {
+	ctx := context.Background()
+	configDir := t.TempDir()
+	t.Setenv("CONFIG_DIR", configDir)
+	t.Setenv("DOTENV_REAL_OF_TEST", "real")
+
+	for name, text := range map[string]string{
+		".env":       "DOTENV_A_OF_TEST=a\nDOTENV_B_OF_TEST=b\nDOTENV_REAL_OF_TEST=dotenv\n",
+		".env.local": "DOTENV_B_OF_TEST=b-local\n",
+	} {
+		if err := os.WriteFile(filepath.Join(configDir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = os.Unsetenv("DOTENV_A_OF_TEST")
+		_ = os.Unsetenv("DOTENV_B_OF_TEST")
+	})
+
+	// it's opt-in
+	app, ww := cleanApp(t, ctx, false)
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "consul"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := os.LookupEnv("DOTENV_A_OF_TEST"); ok || len(ww.dotenvs) > 0 {
+		t.Fatal("expecting no dotenv files loaded by default")
+	}
+
+	app, ww = cleanApp(t, ctx, false, func(s *cli.Config) { s.LoadDotEnv = true })
+	ww.Config.Store = store.New()
+	_, ff := cli.DottedPathToCommandOrFlag1("consul.data-center", ww.root)
+	ff.SetEnvVars("DOTENV_A_OF_TEST")
+	ww.setArgs([]string{app.Name(), "consul"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for k, v := range map[string]string{
+		"DOTENV_A_OF_TEST":    "a",
+		"DOTENV_B_OF_TEST":    "b-local",
+		"DOTENV_REAL_OF_TEST": "real",
+	} {
+		if got := os.Getenv(k); got != v {
+			t.Fatalf("$%s = %q; want %q", k, got, v)
+		}
+	}
+	if e, ok := ww.dotenvs["DOTENV_B_OF_TEST"]; !ok || e.Source() != filepath.Join(configDir, ".env.local")+":1" {
+		t.Fatalf("bad provenance of DOTENV_B_OF_TEST: %+v", e)
+	}
+	if _, ok := ww.dotenvs["DOTENV_REAL_OF_TEST"]; ok {
+		t.Fatal("the real env should win")
+	}
+
+	// the values are masked in the debug screen, except the ones
+	// of the flags which are not secret
+	for _, e := range ww.maskedDotEnvs(ctx) {
+		want := cli.SecretMask
+		if e.Key == "DOTENV_A_OF_TEST" {
+			want = "a"
+		}
+		if e.Value != want {
+			t.Fatalf("%s = %q; want %q", e.Key, e.Value, want)
+		}
+	}
+	ff.SetSecret(true)
+	for _, e := range ww.maskedDotEnvs(ctx) {
+		if e.Value != cli.SecretMask {
+			t.Fatalf("expecting %s masked, got %q", e.Key, e.Value)
+		}
+	}
+}
+
+func TestWorkerS_LoadDotEnvInterpolation(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CONFIG_DIR", configDir)
+	t.Setenv("DOTENV_USER_OF_TEST", "real")
+
+	for name, text := range map[string]string{
+		".env":       "DOTENV_HOST_OF_TEST=a\nDOTENV_PORT_OF_TEST=80\nDOTENV_USER_OF_TEST=dotenv\n",
+		".env.local": "DOTENV_HOST_OF_TEST=b\nDOTENV_URL_OF_TEST=${DOTENV_USER_OF_TEST}@${DOTENV_HOST_OF_TEST}:${DOTENV_PORT_OF_TEST}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(configDir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, k := range []string{"DOTENV_HOST_OF_TEST", "DOTENV_PORT_OF_TEST", "DOTENV_URL_OF_TEST"} {
+			_ = os.Unsetenv(k)
+		}
+	})
+
+	// the real env wins, then the entries of the same file, and
+	// then the ones of the former files.
+	w := &workerS{Config: &cli.Config{LoadDotEnv: true}}
+	w.loadDotEnv(context.Background())
+	if got := os.Getenv("DOTENV_URL_OF_TEST"); got != "real@b:80" {
+		t.Fatalf("$DOTENV_URL_OF_TEST = %q; want %q", got, "real@b:80")
+	}
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// envVarRef describes an environment variable recognized by
+// the app.
+type envVarRef struct {
+	Name    string // the envvar name
+	Type    string // the type of bound flag, or "string"
+	Default string // the default value of bound flag
+	Value   string // the current value, secret value is masked
+	IsSet   bool   // the envvar is present in current environment
+	Secret  bool   // the bound flag is secret
+	BindsTo string // the bound flag, or the description of a builtin envvar
+}
+
+// builtinEnvVars are the envvars exported or recognized by cmdr
+// itself, see also preEnvSet and postEnvLoad.
+var builtinEnvVars = []struct{ name, desc string }{
+	{"APP", "the app name"},
+	{"APPNAME", "the app name"},
+	{"APP_NAME", "the app name"},
+	{"APP_VER", "the app version"},
+	{"APP_VERSION", "the app version"},
+	{"CMDR_VERSION", "the version of cmdr"},
+	{"EXE", "the executable path"},
+	{"EXE_DIR", "the directory of the executable"},
+	{"CONFIG_DIR", "the user config directory"},
+	{"CACHE_DIR", "the user cache directory"},
+	{"DATA_DIR", "the user data directory"},
+	{"LOCAL_SHARE_DIR", "the user data directory"},
+	{"COMMON_SHARE_DIR", "the shared data directory, /usr/local/share/APP"},
+	{"TEMP_DIR", "the temporary directory"},
+	{"PAGER", "the pager for long help screens, 'less -R' by default"},
+	{"FORCE_DEFAULT_ACTION", "invoke the builtin default action instead of the command's"},
+	{"FORCE_RUN", "reset FORCE_DEFAULT_ACTION"},
+}
+
+// envVarsReference collects all envvars recognized by the app:
+// the explicit EnvVars of flags (including the builtin ones such
+// as HELP, VERBOSE and NO_COLOR), the auto-bound names if
+// AutoEnv enabled, and the builtin envvars exported by cmdr.
+func (w *workerS) envVarsReference(ctx context.Context) (refs []envVarRef) {
+	seen := make(map[string]bool)
+	add := func(ref envVarRef) {
+		key := ref.Name + "\x00" + ref.BindsTo
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		ref.Value, ref.IsSet = os.LookupEnv(ref.Name)
+		refs = append(refs, ref)
+	}
+
+	if w.root != nil {
+		if cx, ok := w.root.Cmd.(*cli.CmdS); ok {
+			cx.WalkEverything(ctx, func(cc, pp cli.Cmd, ff *cli.Flag, cmdIndex, flgIndex, level int) {
+				if ff == nil {
+					return
+				}
+				_, _, _, _, _ = cc, pp, cmdIndex, flgIndex, level
+
+				ref := envVarRef{
+					Type:    fmt.Sprintf("%T", ff.DefaultValue()),
+					Default: fmt.Sprint(ff.MaskedValue(ff.DefaultValue())),
+					Secret:  ff.Secret(),
+					BindsTo: flagBindingTitle(ff),
+				}
+				for _, ev := range ff.EnvVars() {
+					ref.Name = ev
+					add(ref)
+				}
+				if w.AutoEnv {
+					ref.Name = ff.GetAutoEnvVarName(w.AutoEnvPrefix, true)
+					add(ref)
+				}
+			})
+		}
+	}
+
+	for _, b := range builtinEnvVars {
+		add(envVarRef{Name: b.name, Type: "string", BindsTo: b.desc})
+	}
+
+	// mask the secret values
+	for i := range refs {
+		if refs[i].IsSet && refs[i].Secret && refs[i].Value != "" {
+			refs[i].Value = cli.SecretMask
+		}
+	}
+
+	slices.SortStableFunc(refs, func(a, b envVarRef) int { return strings.Compare(a.Name, b.Name) })
+	return
+}
+
+// flagBindingTitle returns a title like `--data-center (consul)`.
+func flagBindingTitle(ff *cli.Flag) string {
+	if dp := ff.Owner().GetDottedPath(); dp != "" {
+		return fmt.Sprintf("--%s (%s)", ff.LongTitle(), dp)
+	}
+	return "--" + ff.LongTitle()
+}
+
+// printEnvVarsReference prints the envvars reference in the
+// given format: "table" (default), "export" or "markdown".
+func printEnvVarsReference(wr io.Writer, refs []envVarRef, format string) (err error) {
+	switch format {
+	case "export", "sh", "shell":
+		for _, ref := range refs {
+			if ref.Secret {
+				// never export a secret, even a masked one
+				if _, err = fmt.Fprintf(wr, "# %s (%s, secret)\n# export %s=\n", ref.BindsTo, ref.Type, ref.Name); err != nil {
+					return
+				}
+				continue
+			}
+			val := ref.Default
+			if ref.IsSet {
+				val = ref.Value
+			}
+			if _, err = fmt.Fprintf(wr, "# %s (%s)\nexport %s=%s\n", ref.BindsTo, ref.Type, ref.Name, shellQuote(val)); err != nil {
+				return
+			}
+		}
+
+	case "markdown", "md":
+		_, _ = fmt.Fprintln(wr, "| Name | Type | Default | Binds to |")
+		_, _ = fmt.Fprintln(wr, "| ---- | ---- | ------- | -------- |")
+		for _, ref := range refs {
+			if _, err = fmt.Fprintf(wr, "| `%s` | %s | %s | %s |\n", ref.Name, ref.Type,
+				markdownCell(ref.Default), markdownCell(ref.BindsTo)); err != nil {
+				return
+			}
+		}
+
+	case "", "table":
+		tw := tabwriter.NewWriter(wr, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "NAME\tTYPE\tDEFAULT\tVALUE\tBINDS TO")
+		for _, ref := range refs {
+			val := "-"
+			if ref.IsSet {
+				val = ref.Value
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ref.Name, ref.Type, ref.Default, val, ref.BindsTo)
+		}
+		err = tw.Flush()
+
+	default:
+		err = fmt.Errorf("unknown format %q for envvars reference, expecting table, export or markdown", format)
+	}
+	return
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_EnvVarsReference(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("VERBOSE", "1")
+
+	var sb strings.Builder
+	app, ww := cleanApp(t, ctx, false, withHelpScreenWriter(&sb))
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "~~env", "--all", "--format=export"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	text := sb.String()
+	for _, want := range []string{
+		"# --verbose (bool)\nexport VERBOSE='1'\n", // builtin flag, current value
+		"export CONFIG_DIR=",                       // exported by preEnvSet
+		"export APP_PROFILE=",                      // the profile flag
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expecting %q in the envvars reference, but got:\n%s", want, text)
+		}
+	}
+
+	sb.Reset()
+	if err := printEnvVarsReference(&sb, ww.envVarsReference(ctx), "table"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sb.String(), "NAME ") {
+		t.Fatalf("bad table header: %q", sb.String())
+	}
+	if err := printEnvVarsReference(&sb, nil, "unknown"); err == nil {
+		t.Fatal("expecting error for unknown format")
+	}
+}
+
+func TestWorkerS_EnvVarsReferenceSecret(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("DC_OF_TEST", "s3cr3t")
+
+	var sb strings.Builder
+	app, ww := cleanApp(t, ctx, false, withHelpScreenWriter(&sb))
+	ww.Config.Store = store.New()
+	_, ff := cli.DottedPathToCommandOrFlag1("consul.data-center", ww.root)
+	ff.SetEnvVars("DC_OF_TEST")
+	ff.SetSecret(true)
+	ff.SetDefaultValue("") // a secret without default value
+
+	ww.setArgs([]string{app.Name(), "~~env", "--all", "--format=export"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	text := sb.String()
+	if strings.Contains(text, "s3cr3t") || strings.Contains(text, "\nexport DC_OF_TEST=") ||
+		!strings.Contains(text, "# export DC_OF_TEST=\n") {
+		t.Fatalf("expecting the secret line commented out, but got:\n%s", text)
+	}
+
+	for _, ref := range ww.envVarsReference(ctx) {
+		if ref.Name == "DC_OF_TEST" && (!ref.Secret || ref.Value != cli.SecretMask) {
+			t.Fatalf("expecting the secret value masked, got %+v", ref)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hedzr/is/states"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// reportError suggests the exit code of the final error, and
+// prints the error if [cli.Config.PresentErrors] is set or
+// `--error-format` is given.
+func (w *workerS) reportError(ctx context.Context) {
+	err := w.finalError()
+	if err == nil {
+		return
+	}
+	w.suggestRetCode(err)
+
+	format := w.errorFormat
+	if format == "" && w.Config.PresentErrors {
+		format = w.Config.ErrorFormat
+		if format == "" {
+			format = "text"
+		}
+	}
+	if format == "" {
+		return
+	}
+
+	var lastCmd cli.Cmd
+	if w.parsingCtx != nil {
+		lastCmd = w.parsingCtx.LastCmd()
+	}
+	r := cli.NewErrorReport(err, lastCmd)
+	r.Code = w.retCode // the one set by action takes precedence
+	r.Kind = cli.ExitCodeName(r.Code)
+
+	var wr io.Writer = os.Stderr
+	if w.Config.ErrorWriter != nil {
+		wr = w.Config.ErrorWriter
+	}
+	var e error
+	if strings.EqualFold(format, "json") {
+		e = r.WriteJSON(wr)
+	} else {
+		e = r.WriteText(wr, states.Env().CountOfVerbose() > 0)
+	}
+	if e != nil {
+		logz.ErrorContext(ctx, "[cmdr] cannot present the error", "err", e)
+	}
+}
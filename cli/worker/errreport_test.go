@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_ReportError(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(present bool, actionErr error, args ...string) (ww *workerS, out string, err error) {
+		var eb strings.Builder
+		ta := newTestApp(t, ctx, func(s *cli.Config) {
+			s.PresentErrors, s.ErrorWriter = present, &eb
+		})
+		ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			return actionErr
+		})
+		err = ta.run(ctx, args...)
+		return ta.ww, eb.String(), err
+	}
+
+	errBoom := errors.New("boom")
+	ww, out, err := run(false, errBoom, "consul")
+	if !errors.Is(err, errBoom) || ww.SuggestRetCode() != cli.ExitFailure {
+		t.Fatalf("expecting exit code %d for %v, got %d", cli.ExitFailure, err, ww.SuggestRetCode())
+	}
+	if out != "" {
+		t.Fatalf("expecting nothing presented, got %q", out)
+	}
+
+	if _, out, _ = run(true, errBoom, "consul"); out != "error: boom\n" {
+		t.Fatalf("expecting the concise line, got %q", out)
+	}
+
+	errRequired := cli.LocalizeError(cli.MsgErrRequiredFlag, cli.ErrRequiredFlag, "file", "consul")
+	ww, out, _ = run(false, errRequired, "--error-format=json", "consul")
+	var r cli.ErrorReport
+	if e := json.Unmarshal([]byte(out), &r); e != nil {
+		t.Fatalf("expecting a json report, got %q: %v", out, e)
+	}
+	if r.Code != cli.ExitUsage || r.Kind != "usage" || r.Command != "consul" || len(r.Hints) == 0 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	if ww.SuggestRetCode() != cli.ExitUsage {
+		t.Fatalf("expecting exit code %d, got %d", cli.ExitUsage, ww.SuggestRetCode())
+	}
+}
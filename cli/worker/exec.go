@@ -6,12 +6,12 @@ import (
 	"slices"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	errorsv3 "gopkg.in/hedzr/errors.v3"
 
 	"github.com/hedzr/cmdr/v2/cli"
 	"github.com/hedzr/cmdr/v2/pkg/logz"
-	"github.com/hedzr/is/exec"
 )
 
 func (w *workerS) SetTasksAfterRun(tasks ...taskAfterRun) {
@@ -47,6 +47,21 @@ func (w *workerS) exec(ctx context.Context, pc *parseCtx) (err error) {
 	return w.execCmd(ctx, pc, lastCmd, forceDefaultAction)
 }
 
+// invoker composes the global middlewares and the ones of the
+// command subtree around cmd.Invoke. The deadline of the action
+// is the innermost one, so that the outer middlewares see the
+// TimeoutError.
+func (w *workerS) invoker(cmd cli.Cmd, timeout time.Duration) cli.OnInvokeHandler {
+	invoke := func(ctx context.Context, cmd cli.Cmd, args []string) error {
+		return cmd.Invoke(ctx, args)
+	}
+	mws := append(slices.Clone(w.Config.Middlewares), cli.MiddlewaresOf(cmd)...)
+	if timeout > 0 {
+		mws = append(mws, cli.TimeoutMiddleware(timeout))
+	}
+	return cli.Chain(invoke, mws...)
+}
+
 func (w *workerS) execCmd(ctx context.Context, pc *parseCtx, cmd cli.Cmd, forceDefaultAction bool) (err error) {
 	var deferActions func(errInvoked error)
 	if deferActions, err = w.beforeExec(ctx, pc, cmd); err != nil {
@@ -73,30 +88,41 @@ func (w *workerS) execCmd(ctx context.Context, pc *parseCtx, cmd cli.Cmd, forceD
 		return
 	}
 
+	timeout := w.timeoutOf(cmd)
+
+	if cmd.CanInvoke() && !forceDefaultAction || cmd.InvokeShell() != "" || cmd.InvokeProc() != "" {
+		if err = w.confirmCmd(ctx, pc, cmd); err != nil {
+			return
+		}
+	}
+
 	if is := cmd.InvokeShell(); is != "" {
 		if !cmd.CanInvoke() {
-			if sh := cmd.Shell(); sh != "" {
-				err = exec.New().WithCommand(sh, "-c", is).RunAndCheckError()
+			if pc.DryRun() {
+				if sh := cmd.Shell(); sh != "" {
+					is = sh + " -c " + shellQuote(is)
+				}
+				err = w.printDryRun(is)
 				return
 			}
-			err = exec.New().WithCommandString(is).RunAndCheckError()
+			err = w.invokeAlias(ctx, cmd, timeout, is, cmd.Shell(), false)
 			return
 		}
 	}
 	if ip := cmd.InvokeProc(); ip != "" {
 		if !cmd.CanInvoke() {
-			if strings.Contains(ip, "sh -c ") {
-				err = exec.New().WithCommandString(ip).RunAndCheckError()
-			} else {
-				err = exec.Call(ip, nil)
+			if pc.DryRun() {
+				err = w.printDryRun(ip)
+				return
 			}
+			err = w.invokeAlias(ctx, cmd, timeout, ip, "", true)
 			return
 		}
 	}
 
 	if !forceDefaultAction && cmd.CanInvoke() {
 		logz.VerboseContext(ctx, "invoke action of cmd, with args", "cmd", cmd, "args", pc.positionalArgs)
-		err = cmd.Invoke(ctx, pc.positionalArgs)
+		err = w.invoker(cmd, timeout)(cli.WithOutputter(ctx, w.outputter()), cmd, pc.positionalArgs)
 		logz.VerboseContext(ctx, "invoke action ends.", "err", err)
 		if !w.errIsSignalFallback(err) {
 			return
@@ -157,6 +183,10 @@ func (w *workerS) handleActions(ctx context.Context, pc *parseCtx) (handled bool
 
 func (w *workerS) beforeExec(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd) (deferActions func(errInvoked error), err error) {
 	deferActions = func(error) {}
+	err = w.resolveSecretFlags(ctx, pc, lastCmd)
+	if err != nil {
+		return
+	}
 	err = w.checkRequiredFlags(ctx, pc, lastCmd)
 	if err != nil {
 		return
@@ -165,6 +195,11 @@ func (w *workerS) beforeExec(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd)
 	if err != nil {
 		return
 	}
+	if w.saveConfig {
+		if err = w.saveConfigFile(ctx, pc); err != nil {
+			return
+		}
+	}
 
 	if lastCmd != w.root.Cmd {
 		if cx, ok := w.root.Cmd.(*cli.CmdS); ok {
@@ -178,23 +213,28 @@ func (w *workerS) checkValidArgs(ctx context.Context, pc *parseCtx, lastCmd cli.
 	for ff, ms := range pc.matchedFlags {
 		val := fmt.Sprintf("%v", ms.Value)
 		if ff != nil && len(ff.ValidArgs()) > 0 && !slices.Contains(ff.ValidArgs(), val) {
-			err = cli.ErrValidArgs.FormatWith(ff, ff.ValidArgs(), lastCmd)
+			err = cli.LocalizeError(cli.MsgErrValidArgs, cli.ErrValidArgs, ff, ff.ValidArgs(), lastCmd)
 		}
 	}
 	_ = ctx
 	return
 }
 
-func (w *workerS) checkRequiredFlags(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd) (err error) { //nolint:revive
+// checkRequiredFlags returns ErrRequiredFlag for the first
+// missing required flag, or prompts for the missing ones if
+// possible, see canPrompt.
+func (w *workerS) checkRequiredFlags(ctx context.Context, pcx *parseCtx, lastCmd cli.Cmd) (err error) { //nolint:revive
 	wbc := &cli.WalkBackwardsCtx{
 		Group: true,
 		Sort:  false,
 	}
-	_ = pc
 	lastCmd.WalkBackwardsCtx(ctx, func(ctx context.Context, pc *cli.WalkBackwardsCtx, cc cli.Cmd, ff *cli.Flag, index, groupIndex, count, level int) {
-		if ff != nil {
+		if ff != nil && err == nil {
 			if ff.Required() && ff.GetTriggeredTimes() <= 0 {
-				err = cli.ErrRequiredFlag.FormatWith(ff, lastCmd)
+				if w.canPrompt() && w.promptFlag(ctx, pcx, ff) == nil {
+					return
+				}
+				err = cli.LocalizeError(cli.MsgErrRequiredFlag, cli.ErrRequiredFlag, ff, lastCmd)
 				_, _, _, _, _, _ = pc, cc, index, groupIndex, count, level
 				return
 			}
@@ -258,7 +298,7 @@ func (w *workerS) onUnknownCommandMatched(ctx context.Context, pc *parseCtx) (er
 	// if ignoreTestArgs && strings.HasPrefix(pc.arg,"test."){
 	// 	return
 	// }
-	err = cli.ErrUnmatchedCommand.FormatWith(pc.arg, pc.LastCmd())
+	err = cli.LocalizeError(cli.MsgErrUnmatchedCommand, cli.ErrUnmatchedCommand, pc.arg, pc.LastCmd())
 	if w.OnUnknownCommandHandler != nil {
 		err = w.OnUnknownCommandHandler(ctx, pc.arg, pc.LastCmd(), err)
 	}
@@ -274,7 +314,7 @@ func (w *workerS) onUnknownFlagMatched(ctx context.Context, pc *parseCtx) (err e
 	if ignoreTestArgs && strings.HasPrefix(pc.arg, "test.") {
 		return
 	}
-	err = cli.ErrUnmatchedFlag.FormatWith(pc.arg, pc.LastCmd())
+	err = cli.LocalizeError(cli.MsgErrUnmatchedFlag, cli.ErrUnmatchedFlag, pc.arg, pc.LastCmd())
 	if w.OnUnknownFlagHandler != nil {
 		err = w.OnUnknownFlagHandler(ctx, pc.arg, pc.LastCmd(), err)
 	}
@@ -1,6 +1,12 @@
 package worker
 
 import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+
 	"github.com/hedzr/cmdr/v2/cli"
 )
 
@@ -8,6 +14,64 @@ func rootCmdForTesting() (root *cli.RootCommand) { //nolint:unused //for test
 	return nil
 }
 
+// testApp is a clean demo app for the run-level tests. Its help
+// screen and outputs are captured in sb.
+type testApp struct {
+	app    cli.App
+	ww     *workerS
+	sb     *strings.Builder
+	consul *cli.CmdS // the 'consul' command, to set a test action
+}
+
+// newTestApp builds the demo app with opts, with an empty Store
+// and without the forced default action.
+func newTestApp(t *testing.T, ctx context.Context, opts ...cli.Opt) (ta *testApp) { //nolint:revive
+	ta = &testApp{sb: new(strings.Builder)}
+	ta.app, ta.ww = cleanApp(t, ctx, false, append([]cli.Opt{withHelpScreenWriter(ta.sb)}, opts...)...)
+	ta.ww.Config.Store = store.New()
+	ta.ww.ForceDefaultAction = false
+
+	var ok bool
+	if ta.consul, ok = ta.ww.root.Cmd.FindSubCommand(ctx, "consul", false).(*cli.CmdS); !ok {
+		t.Fatal("command 'consul' not found")
+	}
+	return
+}
+
+// removeCommand removes the command name from the demo app, such
+// as the 'server' which shadows the builtin one. It must be done
+// before running.
+func (ta *testApp) removeCommand(name string) {
+	root := ta.ww.root.Cmd.(*cli.CmdS)
+	var cmds []*cli.CmdS
+	for _, cc := range root.SubCommands() {
+		if cc.Name() != name {
+			cmds = append(cmds, cc)
+		}
+	}
+	root.SetCommands(cmds...)
+}
+
+// removeFlag removes the root flag long from the demo app, such
+// as the '--dry-run' which shadows the builtin one. It must be
+// done before running.
+func (ta *testApp) removeFlag(long string) {
+	root := ta.ww.root.Cmd.(*cli.CmdS)
+	var flags []*cli.Flag
+	for _, ff := range root.Flags() {
+		if ff.Long != long {
+			flags = append(flags, ff)
+		}
+	}
+	root.SetFlags(flags...)
+}
+
+// run runs the app with the command-line args.
+func (ta *testApp) run(ctx context.Context, args ...string) error {
+	ta.ww.setArgs(append([]string{ta.app.Name()}, args...))
+	return ta.ww.Run(ctx)
+}
+
 //
 
 //
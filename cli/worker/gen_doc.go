@@ -3,8 +3,13 @@ package worker
 import (
 	"context"
 	"fmt"
+	"os"
+	"path"
+	"strings"
 
 	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/is/dir"
+	"gopkg.in/hedzr/errors.v3"
 )
 
 type genDocS struct{}
@@ -12,9 +17,132 @@ type genDocS struct{}
 func (w *genDocS) onAction(ctx context.Context, cmd cli.Cmd, args []string) (err error) { //nolint:revive,unused
 	outDir := cmd.Store().MustString("dir")
 	fmt.Printf("# generating docpages (output-dir: %s) ...\n", outDir)
+
+	app := cmd.Root().App()
+	worker, ok := app.GetRunner().(*workerS)
+	if !ok {
+		return errors.New("invalid workerS object")
+	}
+
+	// the envvars reference
+	if outDir == "" {
+		outDir = "."
+	}
+	if err = dir.EnsureDir(outDir); err != nil {
+		return
+	}
+	if err = genHelpTopicDocs(outDir, app.Name(), worker.helpTopics()); err != nil {
+		return
+	}
+	if err = genCommandDocs(ctx, outDir, worker, cmd.Root().Cmd); err != nil {
+		return
+	}
+	name := path.Join(outDir, app.Name()+"-env.md")
+	fmt.Printf("#    writing to %s...\n", name)
+	var f *os.File
+	if f, err = os.Create(name); err != nil {
+		return
+	}
+	defer func() {
+		if e := f.Close(); err == nil {
+			err = e
+		}
+	}()
+	_, _ = fmt.Fprintf(f, "# Environment Variables of %s\n\n", app.Name())
+	err = printEnvVarsReference(f, worker.envVarsReference(ctx), "markdown")
 	return
 }
 
+// genCommandDocs writes the commands reference, with the notes,
+// related commands and versions, into `APP-commands.md`.
+func genCommandDocs(ctx context.Context, outDir string, w *workerS, root cli.Cmd) (err error) {
+	var pc cli.ParsedState
+	if w.parsingCtx != nil {
+		pc = w.parsingCtx
+	}
+	tree := w.exportTree(ctx, pc, root)
+	if tree == nil {
+		return
+	}
+
+	appName := root.Root().AppName
+	name := path.Join(outDir, appName+"-commands.md")
+	fmt.Printf("#    writing to %s...\n", name)
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "# %s\n", cli.Tf(cli.MsgCommandsOf, appName))
+	printCommandDoc(&sb, appName, tree)
+	err = os.WriteFile(name, []byte(sb.String()), 0o644)
+	return
+}
+
+func printCommandDoc(sb *strings.Builder, appName string, node *cmdNode) {
+	if node.Hidden || node.VendorHidden {
+		return
+	}
+
+	title := appName
+	if node.Path != "" {
+		title = seeAlsoTitle(appName, node.Path)
+	}
+	_, _ = fmt.Fprintf(sb, "\n## %s\n\n", title)
+	if node.Since != "" {
+		_, _ = fmt.Fprintf(sb, "*%s*\n\n", cli.Tf(cli.MsgSince, node.Since))
+	}
+	if desc := node.LongDescription; desc != "" {
+		_, _ = fmt.Fprintf(sb, "%s\n\n", desc)
+	} else if node.Description != "" {
+		_, _ = fmt.Fprintf(sb, "%s\n\n", node.Description)
+	}
+	if node.Examples != "" {
+		_, _ = fmt.Fprintf(sb, "%s:\n\n```bash\n%s\n```\n\n", cli.T(cli.MsgExamples), node.Examples)
+	}
+
+	var notes []string
+	notes = append(notes, node.Notes...)
+	seeAlso := append([]string{}, node.SeeAlso...)
+	var flags []string
+	for _, fn := range node.Flags {
+		if fn.Hidden || fn.VendorHidden {
+			continue
+		}
+		titles := []string{"`--" + fn.Name + "`"}
+		for _, s := range fn.Shorts {
+			titles = append(titles, "`-"+s+"`")
+		}
+		row := "- " + strings.Join(titles, ", ")
+		if fn.Description != "" {
+			row += ": " + fn.Description
+		}
+		if fn.Since != "" {
+			row += fmt.Sprintf(" *(%s)*", cli.Tf(cli.MsgSince, fn.Since))
+		}
+		flags = append(flags, row)
+		for _, note := range fn.Notes {
+			notes = append(notes, "`--"+fn.Name+"`: "+note)
+		}
+		seeAlso = append(seeAlso, fn.SeeAlso...)
+	}
+	if len(flags) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s:\n\n%s\n\n", cli.T(cli.MsgFlags), strings.Join(flags, "\n"))
+	}
+	if len(notes) > 0 {
+		_, _ = fmt.Fprintf(sb, "%s:\n\n- %s\n\n", cli.T(cli.MsgNotes), strings.Join(notes, "\n- "))
+	}
+	if len(seeAlso) > 0 {
+		var links []string
+		for _, dp := range seeAlso {
+			t := seeAlsoTitle(appName, dp)
+			links = append(links, fmt.Sprintf("[%s](#%s)", t, strings.ReplaceAll(t, " ", "-")))
+		}
+		_, _ = fmt.Fprintf(sb, "%s: %s\n\n", cli.T(cli.MsgSeeAlso), strings.Join(links, ", "))
+	}
+
+	for _, child := range node.Commands {
+		printCommandDoc(sb, appName, child)
+	}
+}
+
 //
 //
 // /////////////////////////////////////////
@@ -50,6 +50,16 @@ func (w *genManS) onAction(ctx context.Context, cmd cli.Cmd, args []string) (err
 			fmt.Printf("#    writing to %s...\n", name)
 			genManpage(ctx, name, worker, pc, cc)
 		})
+
+		if cx.OwnerIsNil() {
+			for _, t := range worker.helpTopics() {
+				name := path.Join(outDir, helpTopicPageName(appName, t)+".man")
+				fmt.Printf("#    writing to %s...\n", name)
+				if err = genHelpTopicManpage(name, cx.Root(), t); err != nil {
+					return
+				}
+			}
+		}
 	}
 
 	fmt.Printf("#    DONE.\n")
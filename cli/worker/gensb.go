@@ -47,6 +47,11 @@ _cmdr_cmd_{{.AppName}}() {
       COMPREPLY=()
       return 0
       ;;
+    help|h|info)
+      # the commands and the help topics, the last line is the directive
+      COMPREPLY=( $($cmd __complete help "${cur}" | sed '$d') )
+      return 0
+      ;;
     $cmd)
       COMPREPLY=( $(compgen -W "$(_cmdr_cmd_help_events $cmd)" -- ${cur}) )
       return 0
@@ -230,11 +230,15 @@ func (g *genzsh) genZshFnByCommand(ctx *genshCtx, fnName string, cmd cli.Cmd) (e
 func (g *genzsh) genZshFnFlagsByCommand(ctx *genshCtx, fnName string, cmd cli.Cmd) (err error) {
 	var descCommands strings.Builder
 
-	if len(cmd.Flags()) > 0 {
+	topics := g.helpTopicsOf(cmd)
+	if len(cmd.Flags()) > 0 || len(topics) > 0 {
 		descCommands.WriteString("    _arguments -s \\\n")
 	}
 
 	g.gzt1(&descCommands, cmd, false)
+	if len(topics) > 0 {
+		descCommands.WriteString(fmt.Sprintf("                '*::topic:(%s)' \\\n", strings.Join(topics, " ")))
+	}
 
 	desc := strings.TrimSuffix(descCommands.String(), " \\\n")
 	decl := ""
@@ -392,6 +396,20 @@ func (g *genzsh) gzChkMEForToggleGroup(toggleGroupName string, v []*cli.Flag) (m
 	return
 }
 
+// helpTopicsOf returns the names of help topics for completing
+// `help <TAB>`.
+func (g *genzsh) helpTopicsOf(cmd cli.Cmd) (names []string) {
+	if !isHelpCommand(cmd) {
+		return
+	}
+	if w, ok := cmd.Root().App().GetRunner().(*workerS); ok {
+		for _, t := range w.helpTopics() {
+			names = append(names, t.Titles()...)
+		}
+	}
+	return
+}
+
 func (g *genzsh) safeZshFnName(s, rest string) string {
 	_ = rest
 	return strings.ReplaceAll(s, "-", "_")
@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/is/exec"
+)
+
+// buildHelpScreen builds the data model of help screen for a
+// cli.HelpPainter.
+//
+// It walks the commands and flags in the same order as the
+// builtin help screen, and skips the hidden items if not in
+// verbose mode.
+func (s *helpPrinter) buildHelpScreen(ctx context.Context, pc cli.ParsedState, cc cli.Cmd, verboseCount int) (screen *cli.HelpScreen) {
+	expand := func(text string) string {
+		if text == "" {
+			return ""
+		}
+		return pc.Translate(os.ExpandEnv(exec.StripLeftTabs(text)))
+	}
+
+	root := cc.Root()
+	app := root.App()
+	tail := "[files...]"
+	if tph := cc.TailPlaceHolder(); tph != "" {
+		tail = tph
+	}
+
+	screen = &cli.HelpScreen{
+		Cmd:         cc,
+		AppName:     app.Name(),
+		Version:     app.Version(),
+		Header:      expand(root.Header()),
+		Usage:       fmt.Sprintf("$ <kbd>%s</kbd> %s [%s]%s", app.Name(), cc.GetCommandTitles(), cli.T(cli.MsgOptions), tail),
+		Description: expand(cc.DescLong()),
+		Examples:    strings.TrimRight(expand(cc.Examples()), "\n "),
+		Footer:      expand(strings.TrimSpace(root.Footer())),
+	}
+
+	if cc.OwnerIsNil() {
+		screen.Topics = s.w.helpTopics()
+	}
+
+	if rt := cc.RedirectTo(); rt != "" {
+		screen.Notes = append(screen.Notes, cli.Tf(cli.MsgRedirectedTo, rt))
+	} else if root.Cmd == cc && root.RedirectTo() != "" {
+		screen.Notes = append(screen.Notes, cli.Tf(cli.MsgRedirectedRoot, root.RedirectTo()))
+	}
+	for _, ln := range s.w.noteLines(cc) {
+		screen.Notes = append(screen.Notes, expand(ln))
+	}
+	for _, dp := range s.w.seeAlsoPaths(cc) {
+		screen.SeeAlso = append(screen.SeeAlso, seeAlsoTitle(app.Name(), dp))
+	}
+	screen.Since = cli.SinceOf(cc)
+
+	hidden := func(hiddenBR, vendorHiddenBR bool) (skip, dim bool) {
+		skip = (hiddenBR && verboseCount < 1) || (vendorHiddenBR && verboseCount < 3)
+		dim = hiddenBR || vendorHiddenBR
+		return
+	}
+
+	var section *cli.HelpFlagSection
+	walkCtx := &cli.WalkBackwardsCtx{
+		Group: !s.w.DontGroupInHelpScreen,
+		Sort:  s.w.SortInHelpScreen,
+	}
+	cc.WalkBackwardsCtx(ctx, func(ctx context.Context, wc *cli.WalkBackwardsCtx, c cli.Cmd, ff *cli.Flag, index, groupIndex, count, level int) {
+		_, _, _, _ = wc, index, groupIndex, count
+
+		if ff == nil {
+			skip, dim := hidden(c.HiddenBR(), c.VendorHiddenBR())
+			if skip || s.w.unreleasedBR(c) {
+				return
+			}
+			row := cli.HelpCommandRow{
+				Cmd:         c,
+				Titles:      c.GetTitleNamesArray(),
+				Description: expand(c.Desc()),
+				Deprecated:  c.Deprecated(),
+				Since:       cli.SinceOf(c),
+				Hidden:      dim,
+			}
+			title := c.GroupHelpTitle()
+			if n := len(screen.CommandGroups); n == 0 || screen.CommandGroups[n-1].Title != title {
+				screen.CommandGroups = append(screen.CommandGroups, cli.HelpCommandGroup{Title: title})
+			}
+			grp := &screen.CommandGroups[len(screen.CommandGroups)-1]
+			grp.Commands = append(grp.Commands, row)
+			return
+		}
+
+		skip, dim := hidden(ff.HiddenBR(), ff.VendorHiddenBR())
+		if skip || s.w.unreleasedFlag(ff) {
+			return
+		}
+
+		if section == nil || section.Owner != c {
+			title := cli.T(cli.MsgGrandpaFlags)
+			switch {
+			case c.OwnerCmd() == nil:
+				title = cli.T(cli.MsgGlobalFlags)
+			case level == 0:
+				title = cli.T(cli.MsgFlags)
+			case level == 1:
+				title = cli.T(cli.MsgParentFlags)
+			}
+			screen.FlagSections = append(screen.FlagSections, cli.HelpFlagSection{Title: title, Owner: c, Level: level})
+			section = &screen.FlagSections[len(screen.FlagSections)-1]
+		}
+
+		row := cli.HelpFlagRow{
+			Flag:        ff,
+			Titles:      ff.GetTitleNamesArray(),
+			PlaceHolder: ff.PlaceHolder(),
+			Description: expand(ff.Desc()),
+			Default:     ff.MaskedValue(ff.DefaultValue()),
+			EnvVars:     ff.EnvVars(),
+			ToggleGroup: ff.ToggleGroup(),
+			Deprecated:  ff.Deprecated(),
+			Since:       ff.Since(),
+			Required:    ff.Required(),
+			Hidden:      dim,
+		}
+		title := ff.GroupHelpTitle()
+		if n := len(section.Groups); n == 0 || section.Groups[n-1].Title != title {
+			section.Groups = append(section.Groups, cli.HelpFlagGroup{Title: title})
+		}
+		grp := &section.Groups[len(section.Groups)-1]
+		grp.Flags = append(grp.Flags, row)
+	}, walkCtx)
+	return
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_HelpPainter(t *testing.T) {
+	ctx := context.Background()
+
+	var sb strings.Builder
+	var got *cli.HelpScreen
+	painter := cli.HelpPainterFunc(func(ctx context.Context, wr cli.HelpWriter, screen *cli.HelpScreen) (err error) {
+		got = screen
+		_, err = wr.WriteString("compact help of " + screen.Cmd.GetDottedPath() + "\n")
+		return
+	})
+
+	app, ww := cleanApp(t, ctx, false, withHelpScreenWriter(&sb), func(s *cli.Config) { s.HelpPainter = painter })
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "consul", "--help"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got == nil {
+		t.Fatal("the custom painter was not invoked")
+	}
+	if sb.String() != "compact help of consul\n" {
+		t.Fatalf("unexpected help screen: %q", sb.String())
+	}
+	if !strings.Contains(got.Usage, app.Name()) || !strings.Contains(got.Usage, "consul") {
+		t.Fatalf("bad usage line: %q", got.Usage)
+	}
+
+	var flags, global *cli.HelpFlagSection
+	for i := range got.FlagSections {
+		switch got.FlagSections[i].Title {
+		case "Flags":
+			flags = &got.FlagSections[i]
+		case "Global Flags":
+			global = &got.FlagSections[i]
+		}
+	}
+	if flags == nil || global == nil {
+		t.Fatalf("expecting 'Flags' and 'Global Flags' sections, but got %+v", got.FlagSections)
+	}
+	row := flags.Groups[0].Flags[0]
+	if row.Flag.LongTitle() != "data-center" || row.Default != "dc-1" {
+		t.Fatalf("unexpected flag row: %+v", row)
+	}
+}
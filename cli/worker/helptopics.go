@@ -0,0 +1,235 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hedzr/is/exec"
+	"github.com/hedzr/is/term/color"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// helpTopic finds a help topic by its name or alias.
+func (w *workerS) helpTopic(name string) *cli.HelpTopic {
+	for _, t := range w.helpTopics() {
+		if t.Match(name) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (w *workerS) helpTopics() []*cli.HelpTopic {
+	if w == nil || w.Config == nil {
+		return nil
+	}
+	return w.HelpTopics
+}
+
+// isHelpCommand tests if cc is the builtin `help` command.
+func isHelpCommand(cc cli.Cmd) bool {
+	return cc != nil && cc.OwnerIsRoot() && cc.Name() == "help"
+}
+
+// printHelpTopics prints the "Help Topics" section, for root
+// command and `help` command.
+func (s *helpPrinter) printHelpTopics(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int) {
+	topics := s.w.helpTopics()
+	if len(topics) == 0 || (!cc.OwnerIsNil() && !isHelpCommand(cc)) {
+		return
+	}
+	_, _, _ = ctx, pc, cols
+
+	th := s.colors()
+	_, _ = sb.WriteString("\n" + cli.T(cli.MsgHelpTopics) + ":\n")
+	for _, t := range topics {
+		left := "  " + strings.Join(t.Titles(), ", ")
+		if w := tabbedW - len(left); w > 0 {
+			left += strings.Repeat(" ", w)
+		}
+		_, _ = sb.WriteString(s.Translate(left, th.Title))
+		_, _ = sb.WriteString("   ")
+		_, _ = sb.WriteString(s.Translate(t.Description, th.Desc))
+		_, _ = sb.WriteString("\n")
+	}
+	_, _ = sb.WriteString(s.Translate("\n  <dim>"+cli.Tf(cli.MsgHintHelpTopic, cc.Root().AppName)+"</dim>\n", color.FgDefault))
+}
+
+// printHelpTopic prints the page of a help topic.
+func (s *helpPrinter) printHelpTopic(ctx context.Context, wr HelpWriter, pc cli.ParsedState, t *cli.HelpTopic) {
+	if s.Translator == nil {
+		s.Translator = color.GetCPT()
+	}
+
+	var sb strings.Builder
+	th := s.colors()
+	_, _ = sb.WriteString(s.Translate(fmt.Sprintf("<b>%s</b>", t.Name), th.Title))
+	if t.Description != "" {
+		_, _ = sb.WriteString(" - ")
+		_, _ = sb.WriteString(s.Translate(t.Description, th.Desc))
+	}
+	_, _ = sb.WriteString("\n\n")
+
+	body := strings.Trim(exec.StripLeftTabs(t.Body), "\n")
+	if pc != nil {
+		body = pc.Translate(body)
+	}
+	text := s.Translate(markdownishToMarkup(body), color.FgDefault)
+	_, _ = sb.WriteString(exec.LeftPad(strings.TrimRight(text, "\n"), 2))
+	_, _ = sb.WriteString("\n")
+
+	s.writePaged(ctx, wr, sb.String())
+}
+
+var (
+	reMdCode   = regexp.MustCompile("`([^`]+)`")
+	reMdBold   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	reMdItalic = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	reMdMark   = regexp.MustCompile(`==([^=]+)==`)
+	reMdHead   = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	reMdBullet = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+)
+
+// markdownishToMarkup converts a Markdown-ish text to the color
+// markups of help screen, such as `<code>` and `<b>`.
+func markdownishToMarkup(body string) string {
+	inline := func(s string) string {
+		s = reMdCode.ReplaceAllString(s, "<code>$1</code>")
+		s = reMdBold.ReplaceAllString(s, "<b>$1</b>")
+		s = reMdItalic.ReplaceAllString(s, "<i>$1</i>")
+		return reMdMark.ReplaceAllString(s, "<mark>$1</mark>")
+	}
+
+	var lines []string
+	fenced := false
+	for _, ln := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(ln), "```") {
+			fenced = !fenced
+			continue
+		}
+		switch {
+		case fenced:
+			lines = append(lines, "    <dim>"+ln+"</dim>")
+		case reMdHead.MatchString(ln):
+			m := reMdHead.FindStringSubmatch(ln)
+			lines = append(lines, "<b>"+inline(m[2])+"</b>")
+		case reMdBullet.MatchString(ln):
+			m := reMdBullet.FindStringSubmatch(ln)
+			lines = append(lines, m[1]+"• "+inline(m[2]))
+		default:
+			lines = append(lines, inline(ln))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var reMdTags = regexp.MustCompile(`</?(code|b|kbd|mark|strong|i|em|dim|u|font)[^>]*>`)
+
+// markdownishToRoff converts a Markdown-ish text to the body of
+// a manpage.
+func markdownishToRoff(body string) string {
+	inline := func(s string) string {
+		s = strings.ReplaceAll(s, `\`, `\e`)
+		s = strings.ReplaceAll(s, "-", `\-`)
+		s = reMdCode.ReplaceAllString(s, `\fB$1\fR`)
+		s = reMdBold.ReplaceAllString(s, `\fB$1\fR`)
+		s = reMdItalic.ReplaceAllString(s, `\fI$1\fR`)
+		s = reMdMark.ReplaceAllString(s, `\fB$1\fR`)
+		s = reMdTags.ReplaceAllStringFunc(s, func(tag string) string {
+			switch {
+			case strings.HasPrefix(tag, "</"):
+				return `\fR`
+			case strings.HasPrefix(tag, "<i"), strings.HasPrefix(tag, "<em"):
+				return `\fI`
+			case strings.HasPrefix(tag, "<dim"), strings.HasPrefix(tag, "<font"), strings.HasPrefix(tag, "<u"):
+				return ""
+			}
+			return `\fB`
+		})
+		if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+			s = `\&` + s
+		}
+		return s
+	}
+
+	var sb strings.Builder
+	fenced, para := false, false
+	for _, ln := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(ln), "```") {
+			if fenced = !fenced; fenced {
+				_, _ = sb.WriteString(".PP\n.RS 4\n.nf\n")
+			} else {
+				_, _ = sb.WriteString(".fi\n.RE\n")
+			}
+			para = false
+			continue
+		}
+		switch {
+		case fenced:
+			_, _ = sb.WriteString(inline(ln) + "\n")
+		case strings.TrimSpace(ln) == "":
+			para = false
+		case reMdHead.MatchString(ln):
+			m := reMdHead.FindStringSubmatch(ln)
+			_, _ = sb.WriteString(".SS " + inline(m[2]) + "\n")
+			para = false
+		case reMdBullet.MatchString(ln):
+			m := reMdBullet.FindStringSubmatch(ln)
+			_, _ = sb.WriteString(".IP \\(bu 2\n" + inline(m[2]) + "\n")
+			para = true
+		default:
+			if !para {
+				_, _ = sb.WriteString(".PP\n")
+				para = true
+			}
+			_, _ = sb.WriteString(inline(strings.TrimSpace(ln)) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// helpTopicPageName returns the page name of a topic, such as
+// `app-help-config-format`.
+func helpTopicPageName(appName string, t *cli.HelpTopic) string {
+	return appName + "-help-" + t.Name
+}
+
+// genHelpTopicManpage writes the manpage of a help topic, in
+// section 7 (miscellaneous).
+func genHelpTopicManpage(filename string, root *cli.RootCommand, t *cli.HelpTopic) (err error) {
+	name := helpTopicPageName(root.AppName, t)
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, ".pc\n.nh\n.TH %s 7 %q %q \"Tool with cmdr\"\nAuto generated by hedzr/cmdr\n\n",
+		strings.ToUpper(name), time.Now().Format("Jan 2006"), root.Version)
+	_, _ = fmt.Fprintf(&sb, ".SH NAME\n.PP\n%s \\- %s\n\n", strings.ReplaceAll(name, "-", `\-`),
+		strings.ReplaceAll(t.Description, "-", `\-`))
+	_, _ = sb.WriteString(".SH DESCRIPTION\n")
+	_, _ = sb.WriteString(markdownishToRoff(exec.StripLeftTabs(t.Body)))
+	_, _ = fmt.Fprintf(&sb, "\n.SH SEE ALSO\n.PP\n\\fB%s(1)\\fP\n", root.AppName)
+	err = os.WriteFile(filename, []byte(sb.String()), 0o644)
+	return
+}
+
+// genHelpTopicDocs writes the markdown pages of help topics into
+// outDir.
+func genHelpTopicDocs(outDir, appName string, topics []*cli.HelpTopic) (err error) {
+	for _, t := range topics {
+		name := path.Join(outDir, helpTopicPageName(appName, t)+".md")
+		fmt.Printf("#    writing to %s...\n", name)
+		text := fmt.Sprintf("# %s\n\n", t.Name)
+		if t.Description != "" {
+			text += t.Description + "\n\n"
+		}
+		text += strings.TrimSpace(exec.StripLeftTabs(t.Body)) + "\n"
+		if err = os.WriteFile(name, []byte(text), 0o644); err != nil {
+			return
+		}
+	}
+	return
+}
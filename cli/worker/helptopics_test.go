@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_HelpTopics(t *testing.T) {
+	ctx := context.Background()
+	topic := &cli.HelpTopic{
+		Name:        "config-format",
+		Aliases:     []string{"cf"},
+		Description: "The format of config files",
+		Body:        "# Config Files\n\nThe config file is `app.json`.\n\n- item one\n- item two\n",
+	}
+
+	run := func(args ...string) string {
+		var sb strings.Builder
+		app, ww := cleanApp(t, ctx, false, withHelpScreenWriter(&sb), func(s *cli.Config) {
+			s.HelpTopics = []*cli.HelpTopic{topic}
+		})
+		ww.Config.Store = store.New()
+		ww.ForceDefaultAction = false
+		ww.setArgs(append([]string{app.Name()}, args...))
+		if err := ww.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return sb.String()
+	}
+
+	if text := run("--help"); !strings.Contains(text, "Help Topics:") || !strings.Contains(text, "config-format, cf") {
+		t.Fatalf("expecting 'Help Topics' section in root help screen, but got:\n%s", text)
+	}
+	if text := run("consul", "--help"); strings.Contains(text, "Help Topics:") {
+		t.Fatalf("expecting no 'Help Topics' section in subcommand help screen, but got:\n%s", text)
+	}
+	if text := run("help", "cf"); !strings.Contains(text, "config-format") || !strings.Contains(text, "• item one") {
+		t.Fatalf("expecting topic page, but got:\n%s", text)
+	}
+	if text := run("__complete", "help", "c"); !strings.HasPrefix(text, "cf\nconfig-format\n") || !strings.HasSuffix(text, "\n:4\n") {
+		t.Fatalf("expecting help topics completed, but got:\n%s", text)
+	}
+	if text := run("__complete", "help", "display", ""); strings.Contains(text, "config-format") || !strings.HasPrefix(text, "amd\nnvidia\nvoodoo\n") {
+		t.Fatalf("expecting subcommands completed, but got:\n%s", text)
+	}
+	if text := run("__complete", "consul", "--data"); !strings.HasPrefix(text, "--data-center\n") {
+		t.Fatalf("expecting flags completed, but got:\n%s", text)
+	}
+}
+
+func TestMarkdownish(t *testing.T) {
+	body := "# Title\n\nUse `app.json` and **this** or *that*.\n- item\n```\n.x *y*\n```"
+
+	if got, expect := markdownishToMarkup(body),
+		"<b>Title</b>\n\nUse <code>app.json</code> and <b>this</b> or <i>that</i>.\n• item\n    <dim>.x *y*</dim>"; got != expect {
+		t.Fatalf("markdownishToMarkup:\nexpect %q\nbut got %q", expect, got)
+	}
+	if got, expect := markdownishToRoff(body),
+		".SS Title\n.PP\nUse \\fBapp.json\\fR and \\fBthis\\fR or \\fIthat\\fR.\n.IP \\(bu 2\nitem\n.PP\n.RS 4\n.nf\n\\&.x \\fIy\\fR\n.fi\n.RE\n"; got != expect {
+		t.Fatalf("markdownishToRoff:\nexpect %q\nbut got %q", expect, got)
+	}
+}
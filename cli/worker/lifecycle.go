@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// Phase returns the current lifecycle phase of Run.
+func (w *workerS) Phase() cli.Phase { return cli.Phase(w.phase.Load()) }
+
+func (w *workerS) setPhase(ctx context.Context, phase cli.Phase) {
+	w.phase.Store(int32(phase))
+	logz.VerboseContext(ctx, "lifecycle phase", "phase", phase)
+}
+
+// finalError returns the collected errors, or nil if there is
+// none.
+func (w *workerS) finalError() error {
+	if w.errs == nil {
+		return nil
+	}
+	if e, ok := w.errs.(interface{ IsEmpty() bool }); ok && e.IsEmpty() {
+		return nil
+	}
+	return w.errs
+}
+
+// cleanup runs TasksPostCleanup in PhaseCleanup. It is always
+// invoked even if an error occurred in the previous phases.
+//
+// The tasks receive the extras: the ParsedState, the positional
+// args and the final error (nil if ok). A panic in a task is
+// recovered as an error, and the errors of all tasks are
+// attached to the collected errors.
+func (w *workerS) cleanup(ctx context.Context, pc *parseCtx) {
+	w.setPhase(ctx, cli.PhaseCleanup)
+	finalErr := w.finalError()
+	for _, tsk := range w.Config.TasksPostCleanup {
+		if tsk != nil {
+			if err := w.invokeCleanupTask(ctx, pc, tsk, finalErr); err != nil {
+				w.errs.Attach(err)
+			}
+		}
+	}
+}
+
+func (w *workerS) invokeCleanupTask(ctx context.Context, pc *parseCtx, tsk cli.Task, finalErr error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("cleanup task panic: %w", e)
+			} else {
+				err = fmt.Errorf("cleanup task panic: %v", r)
+			}
+		}
+	}()
+	return tsk(ctx, pc.LastCmd(), w, pc, pc.PositionalArgs(), finalErr)
+}
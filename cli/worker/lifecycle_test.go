@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_CleanupAlwaysRuns(t *testing.T) {
+	ctx := context.Background()
+	errAction := errors.New("action failed")
+
+	var phases []cli.Phase
+	var afterRun bool
+	var finalErr error
+	record := func(ctx context.Context, cmd cli.Cmd, runner cli.Runner, extras ...any) (err error) {
+		phases = append(phases, runner.Phase())
+		return
+	}
+
+	ta := newTestApp(t, ctx, func(s *cli.Config) {
+		s.TasksBeforeRun = append(s.TasksBeforeRun, record)
+		s.TasksAfterRun = append(s.TasksAfterRun, func(ctx context.Context, cmd cli.Cmd, runner cli.Runner, extras ...any) (err error) {
+			afterRun = true
+			return
+		})
+		s.TasksPostCleanup = append(s.TasksPostCleanup,
+			record,
+			func(ctx context.Context, cmd cli.Cmd, runner cli.Runner, extras ...any) (err error) {
+				if len(extras) > 2 {
+					finalErr, _ = extras[2].(error)
+				}
+				panic("cleanup panic")
+			},
+			record, // still invoked after the panic
+		)
+	})
+	ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+		return errAction
+	})
+
+	err := ta.run(ctx, "consul")
+	if err == nil || !errors.Is(err, errAction) {
+		t.Fatalf("expecting the action error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "cleanup panic") {
+		t.Fatalf("expecting the panic of cleanup task collected, got %v", err)
+	}
+	if !afterRun {
+		t.Fatal("expecting TasksAfterRun invoked although the action failed")
+	}
+	if finalErr == nil || !errors.Is(finalErr, errAction) {
+		t.Fatalf("expecting cleanup task received the final error, got %v", finalErr)
+	}
+	want := []cli.Phase{cli.PhaseBeforeRun, cli.PhaseCleanup, cli.PhaseCleanup}
+	if len(phases) != len(want) || phases[0] != want[0] || phases[1] != want[1] || phases[2] != want[2] {
+		t.Fatalf("expecting phases %v, got %v", want, phases)
+	}
+	if p := ta.ww.Phase(); p != cli.PhaseDone {
+		t.Fatalf("expecting PhaseDone after Run returned, got %v", p)
+	}
+}
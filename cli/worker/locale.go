@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// applyLocale registers the translations in [cli.Config.Messages]
+// and sets the language of help screen and messages.
+//
+// The locale is chosen by (in priority order):
+//
+//   - `--lang LOCALE`, which is applied while the flag matched
+//   - config entry `locale`
+//   - [cli.Config.Locale], see also cmdr.WithLocale()
+//   - envvars LC_ALL, LC_MESSAGES and LANG, if the app has its
+//     own translations, see [cli.Locale]
+func (w *workerS) applyLocale(ctx context.Context) {
+	if w.Config == nil {
+		return
+	}
+	for locale, msgs := range w.Config.Messages {
+		cli.RegisterMessages(locale, msgs)
+	}
+
+	locale := w.Config.Locale
+	if conf := w.Store(); conf != nil {
+		if v := conf.MustString("locale"); v != "" {
+			locale = v
+		}
+	}
+	if locale != "" {
+		cli.SetLocale(locale)
+	}
+	logz.VerboseContext(ctx, "locale applied", "locale", cli.Locale())
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_Locale(t *testing.T) {
+	ctx := context.Background()
+	defer cli.SetLocale("")
+
+	run := func(locale string, args ...string) string {
+		ta := newTestApp(t, ctx, func(s *cli.Config) {
+			s.Locale = locale
+			s.Messages = map[string]map[string]string{
+				"zh": {"cmd.consul.desc": "consul 操作命令集"},
+			}
+		})
+		if err := ta.run(ctx, args...); err != nil {
+			t.Fatal(err)
+		}
+		return ta.sb.String()
+	}
+
+	text := run("en", "--help")
+	for _, want := range []string{"Usage:", "Commands:", "command set for consul operations"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expecting %q in help screen, but got:\n%s", want, text)
+		}
+	}
+
+	text = run("en", "--lang", "zh_CN.UTF-8", "--help")
+	for _, want := range []string{"用法:", "命令:", "consul 操作命令集"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expecting %q in help screen, but got:\n%s", want, text)
+		}
+	}
+
+	text = run("de", "consul", "--help")
+	for _, want := range []string{"Aufruf:", "Optionen", "Diese Hilfe anzeigen (-?)"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expecting %q in help screen, but got:\n%s", want, text)
+		}
+	}
+	if got := manHeading(cli.MsgSeeAlso); got != "SIEHE AUCH" {
+		t.Fatalf("expecting the localized manpage heading, but got %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_Middlewares(t *testing.T) {
+	ctx := context.Background()
+	errDenied := errors.New("denied")
+
+	var trace []string
+	mw := func(name string, deny bool) cli.Middleware {
+		return func(next cli.OnInvokeHandler) cli.OnInvokeHandler {
+			return func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+				trace = append(trace, name)
+				if deny {
+					return errDenied
+				}
+				return next(ctx, cmd, args)
+			}
+		}
+	}
+
+	run := func(deny bool) error {
+		trace = nil
+		ta := newTestApp(t, ctx, func(s *cli.Config) {
+			s.Middlewares = []cli.Middleware{mw("global", false)}
+		})
+		ta.consul.Use(mw("consul", deny))
+		ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			trace = append(trace, "action")
+			return
+		})
+		return ta.run(ctx, "consul")
+	}
+
+	if err := run(false); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"global", "consul", "action"}; !reflect.DeepEqual(trace, want) {
+		t.Fatalf("expecting %v, got %v", want, trace)
+	}
+
+	if err := run(true); !errors.Is(err, errDenied) {
+		t.Fatalf("expecting the error of middleware, got %v", err)
+	}
+	if want := []string{"global", "consul"}; !reflect.DeepEqual(trace, want) {
+		t.Fatalf("expecting the action skipped, %v, got %v", want, trace)
+	}
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hedzr/is/term/color"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// noteLines collects the notes of cc and its own flags, from
+// Notes() and Since(), in markups.
+func (w *workerS) noteLines(cc cli.Cmd) (lines []string) {
+	if v := cli.SinceOf(cc); v != "" {
+		lines = append(lines, cli.Tf(cli.MsgAvailableSince, v))
+	}
+	lines = append(lines, cli.NotesOf(cc)...)
+	for _, ff := range cc.Flags() {
+		if ff.Hidden() || ff.VendorHidden() || w.unreleased(ff.Since()) {
+			continue
+		}
+		title := "<code>" + ff.GetTitleZshFlagName() + "</code>: "
+		if v := ff.Since(); v != "" {
+			lines = append(lines, title+cli.Tf(cli.MsgFlagAvailableSince, v))
+		}
+		for _, note := range ff.Notes() {
+			lines = append(lines, title+note)
+		}
+	}
+	return
+}
+
+// seeAlsoPaths collects the related commands of cc and its own
+// flags, by dotted path.
+func (w *workerS) seeAlsoPaths(cc cli.Cmd) (paths []string) {
+	seen := make(map[string]bool)
+	add := func(list []string) {
+		for _, dp := range list {
+			if dp = strings.Trim(dp, ". "); dp != "" && !seen[dp] {
+				seen[dp] = true
+				paths = append(paths, dp)
+			}
+		}
+	}
+	add(cli.SeeAlsoOf(cc))
+	for _, ff := range cc.Flags() {
+		if !w.unreleased(ff.Since()) {
+			add(ff.SeeAlso())
+		}
+	}
+	return
+}
+
+// seeAlsoTitle returns a related command in the form of command
+// line, such as `app server start`.
+func seeAlsoTitle(appName, dottedPath string) string {
+	return appName + " " + strings.ReplaceAll(dottedPath, ".", " ")
+}
+
+// seeAlsoPageName returns the manpage name of a related command,
+// such as `app-server-start`.
+func seeAlsoPageName(appName, dottedPath string) string {
+	return appName + "-" + strings.ReplaceAll(dottedPath, ".", "-")
+}
+
+// printMetaNotes prints the notes and the related commands of
+// cc. The "Notes" heading is skipped if it has been printed.
+func (s *helpPrinter) printMetaNotes(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, headed bool) {
+	_ = ctx
+	if lines := s.w.noteLines(cc); len(lines) > 0 {
+		if !headed {
+			_, _ = sb.WriteString("\n" + cli.T(cli.MsgNotes) + ":\n\n")
+		} else if !strings.HasSuffix(sb.String(), "\n") {
+			_, _ = sb.WriteString("\n")
+		}
+		for _, ln := range lines {
+			_, _ = sb.WriteString("  - ")
+			_, _ = sb.WriteString(s.translate(pc, ln, color.FgDefault))
+			_, _ = sb.WriteString("\n")
+		}
+	}
+
+	if paths := s.w.seeAlsoPaths(cc); len(paths) > 0 {
+		th := s.colors()
+		appName := cc.Root().AppName
+		_, _ = sb.WriteString("\n" + cli.T(cli.MsgSeeAlso) + ":\n\n")
+		for _, dp := range paths {
+			_, _ = sb.WriteString("  ")
+			_, _ = sb.WriteString(s.Translate(seeAlsoTitle(appName, dp), th.Title))
+			_, _ = sb.WriteString("\n")
+		}
+	}
+}
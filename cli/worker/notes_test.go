@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_NotesSeeAlsoSince(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(args ...string) string {
+		ta := newTestApp(t, ctx)
+		cc := ta.consul
+		cc.SetSince("v2.0")
+		cc.SetNotes("Needs a running consul agent.")
+		cc.SetSeeAlso("kv")
+		ff := cc.Flags()[0]
+		ff.SetSince("v2.1")
+		ff.SetNotes("Defaults to the agent's datacenter.")
+
+		if err := ta.run(ctx, args...); err != nil {
+			t.Fatal(err)
+		}
+		return ta.sb.String()
+	}
+
+	text := run("consul", "--help")
+	for _, want := range []string{
+		"Notes:\n", "Available since", "v2.0", "  - Needs a running consul agent.\n",
+		"available since", "v2.1", "Defaults to the agent's datacenter.\n",
+		"See Also:\n\n", "demo-app kv\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expecting %q in help screen, but got:\n%s", want, text)
+		}
+	}
+
+	if text = run("--version-sim", "v1.9", "--help"); strings.Contains(text, "command set for consul operations") {
+		t.Fatalf("expecting 'consul' hidden by --version-sim, but got:\n%s", text)
+	}
+	if text = run("--version-sim", "v2.0", "consul", "--help"); strings.Contains(text, "--data-center") || !strings.Contains(text, "Notes:") {
+		t.Fatalf("expecting '--data-center' hidden by --version-sim, but got:\n%s", text)
+	}
+}
+
+func TestCmdMetadataOf(t *testing.T) {
+	cc := &cli.CmdS{}
+	cc.SetSince("v2.0")
+	if got := cli.SinceOf(cc); got != "v2.0" {
+		t.Fatalf("expecting SinceOf() = v2.0, but got %q", got)
+	}
+	// a Cmd implementation without CmdMetadata
+	if lite := (struct{ cli.Cmd }{cc}); cli.SinceOf(lite) != "" || cli.NotesOf(lite) != nil || cli.SeeAlsoOf(lite) != nil {
+		t.Fatal("expecting no metadata for a Cmd without CmdMetadata")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b   string
+		expect int
+	}{
+		{"v1.4", "1.4.0", 0},
+		{"v1.10", "v1.9.9", 1},
+		{"v2.0.0-rc1", "v2.0.0", -1},
+		{"v2.0.0-rc2", "v2.0.0-rc1", 1},
+		{"1.2.3+build", "1.2.3", 0},
+		{"v0.9", "v1", -1},
+	} {
+		if got := compareVersions(tc.a, tc.b); got != tc.expect {
+			t.Fatalf("compareVersions(%q, %q) = %d, expect %d", tc.a, tc.b, got, tc.expect)
+		}
+	}
+}
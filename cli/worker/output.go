@@ -0,0 +1,388 @@
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/hedzr/is"
+	"github.com/hedzr/is/states"
+	"github.com/hedzr/is/term"
+	"github.com/hedzr/is/term/color"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// builtinOutputs adds the flags '--output', '--columns' and
+// '--template' if [cli.Config.OutputFormat] is set.
+//
+// The flags of app with the same titles take precedence, see
+// builtinTitles.
+func (w *workerS) builtinOutputs(app cli.App, p *cli.CmdS) {
+	if w.Config.OutputFormat == "" {
+		return
+	}
+
+	if short, ok := builtinTitles(p, "output", "o"); ok {
+		w.builtinOutputFlag(app, p, short)
+	}
+	if _, ok := builtinTitles(p, "columns", ""); ok {
+		w.builtinColumnsFlag(app, p)
+	}
+	if _, ok := builtinTitles(p, "template", ""); ok {
+		w.builtinTemplateFlag(app, p)
+	}
+}
+
+func (w *workerS) builtinOutputFlag(app cli.App, p *cli.CmdS, short string) {
+	app.NewFlgFrom(p, "", func(b cli.FlagBuilder) {
+		b.Titles("output", short).
+			Description("The output format of the results: table, json, yaml or template").
+			Group(cli.SysMgmtGroup).
+			PlaceHolder("FORMAT").
+			ValidArgs(cli.OutputFormats()...).
+			Examples(`
+$ {{.AppName}} --output json
+	print the results in json, for scripts
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				var ok bool
+				w.outputFormat, ok = hitState.Value.(string)
+				if !ok {
+					err = fmt.Errorf("value is not a string. [value=%v]", hitState.Value)
+				}
+				return
+			})
+	})
+}
+
+func (w *workerS) builtinColumnsFlag(app cli.App, p *cli.CmdS) {
+	app.NewFlgFrom(p, "", func(b cli.FlagBuilder) {
+		b.Titles("columns", "", "cols").
+			Description("The columns of the table output, separated by comma").
+			Group(cli.SysMgmtGroup).
+			PlaceHolder("COLUMNS").
+			Examples(`
+$ {{.AppName}} --columns name,status
+	print the columns 'name' and 'status' only, in this order
+`).
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				var ok bool
+				w.outputColumns, ok = hitState.Value.(string)
+				if !ok {
+					err = fmt.Errorf("value is not a string. [value=%v]", hitState.Value)
+				}
+				return
+			})
+	})
+}
+
+func (w *workerS) builtinTemplateFlag(app cli.App, p *cli.CmdS) {
+	app.NewFlgFrom(p, "", func(b cli.FlagBuilder) {
+		b.Titles("template", "").
+			Description("The Go text/template for the results, implies '--output template'").
+			Group(cli.SysMgmtGroup).
+			PlaceHolder("TEMPLATE").
+			OnMatched(func(f *cli.Flag, position int, hitState *cli.MatchState) (err error) {
+				var ok bool
+				w.outputTemplate, ok = hitState.Value.(string)
+				if !ok {
+					err = fmt.Errorf("value is not a string. [value=%v]", hitState.Value)
+				}
+				return
+			})
+	})
+}
+
+// outputS implements cli.Outputter with the builtin flags
+// '--output', '--columns' and '--template'.
+type outputS struct {
+	wr       io.Writer
+	format   string
+	columns  []string
+	template string
+}
+
+// outputter returns the cli.Outputter for the action, see
+// cli.Output().
+func (w *workerS) outputter() *outputS {
+	o := &outputS{
+		wr:       (&helpPrinter{w: w}).safeGetWriter(),
+		format:   w.outputFormat,
+		template: w.outputTemplate,
+	}
+	for _, col := range strings.Split(w.outputColumns, ",") {
+		if col = strings.TrimSpace(col); col != "" {
+			o.columns = append(o.columns, col)
+		}
+	}
+	if o.format == "" && o.template != "" {
+		o.format = cli.OutputTemplate
+	}
+	if o.format == "" && w.Config != nil {
+		o.format = w.Config.OutputFormat
+	}
+	if o.format == "" {
+		o.format = cli.OutputTable
+	}
+	return o
+}
+
+func (o *outputS) Format() string { return o.format }
+
+func (o *outputS) Write(v any) (err error) {
+	var data []byte
+	switch o.format {
+	case cli.OutputJSON:
+		data, err = marshalJSON(v, "  ")
+	case cli.OutputYAML:
+		data, err = jsonToYAML(v)
+	case cli.OutputTemplate:
+		data, err = o.execTemplate(v)
+	case cli.OutputTable:
+		data, err = o.table(v)
+	default:
+		err = fmt.Errorf("unknown output format %q, expecting one of %v", o.format, cli.OutputFormats())
+	}
+	if err == nil {
+		_, err = o.wr.Write(data)
+	}
+	return
+}
+
+func (o *outputS) execTemplate(v any) (data []byte, err error) {
+	if o.template == "" {
+		return nil, fmt.Errorf("the output format %q needs '--template'", o.format)
+	}
+	var tmpl *template.Template
+	if tmpl, err = template.New("output").Parse(o.template); err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, v); err != nil {
+		return
+	}
+	if buf.Len() > 0 && buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// table renders v as aligned columns. A scalar is printed as is.
+func (o *outputS) table(v any) (data []byte, err error) {
+	cols, rows, ok := tabulate(v)
+	if !ok {
+		if rv := indirect(reflect.ValueOf(v)); rv.IsValid() {
+			data = []byte(cellOf(rv) + "\n")
+		}
+		return
+	}
+	var idx []int
+	if idx, err = selectColumns(cols, o.columns); err != nil {
+		return
+	}
+
+	quiet := states.Env().IsQuietMode()
+	if quiet && len(idx) > 1 {
+		idx = idx[:1] // the identities only, like 'docker ps -q'
+	}
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	if !quiet {
+		header := make([]string, len(idx))
+		for i, ix := range idx {
+			header[i] = strings.ToUpper(cols[ix])
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	line := make([]string, len(idx))
+	for _, row := range rows {
+		for i, ix := range idx {
+			line[i] = row[ix]
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(line, "\t"))
+	}
+	if err = tw.Flush(); err != nil || quiet || !o.colorful() {
+		return buf.Bytes(), err
+	}
+
+	// highlight the header after aligning, the escapes would
+	// break the widths of tabwriter.
+	header, body, _ := bytes.Cut(buf.Bytes(), []byte{'\n'})
+	var sb strings.Builder
+	color.GetCPT().HighlightFast(&sb, string(header))
+	sb.WriteByte('\n')
+	sb.Write(body)
+	return []byte(sb.String()), nil
+}
+
+func (o *outputS) colorful() bool {
+	if is.NoColorMode() {
+		return false
+	}
+	f, ok := o.wr.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// selectColumns returns the indices of the wanted columns, or
+// all columns if wanted is empty. The names are case-insensitive.
+func selectColumns(cols, wanted []string) (idx []int, err error) {
+	if len(wanted) == 0 {
+		for i := range cols {
+			idx = append(idx, i)
+		}
+		return
+	}
+	for _, name := range wanted {
+		i := slices.IndexFunc(cols, func(col string) bool { return strings.EqualFold(col, name) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q, expecting one of: %s", name, strings.Join(cols, ", "))
+		}
+		idx = append(idx, i)
+	}
+	return
+}
+
+// tabulate splits v into the columns and rows. A slice or array
+// has one row for each item, a struct or map is one row. ok is
+// false for a scalar.
+func tabulate(v any) (cols []string, rows [][]string, ok bool) {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return
+	}
+
+	var items []reflect.Value
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return // []byte
+		}
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, indirect(rv.Index(i)))
+		}
+	case reflect.Struct, reflect.Map:
+		items = append(items, rv)
+	default:
+		return
+	}
+	ok = true
+
+	elem := rv.Type()
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		elem = elem.Elem()
+	}
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Interface && len(items) > 0 && items[0].IsValid() {
+		elem = items[0].Type() // []any, by the first item
+	}
+
+	switch elem.Kind() {
+	case reflect.Struct:
+		fields := structColumns(elem)
+		for _, f := range fields {
+			cols = append(cols, f.name)
+		}
+		for _, item := range items {
+			row := make([]string, len(fields))
+			if item.IsValid() && item.Type() == elem {
+				for i, f := range fields {
+					if fv, err := item.FieldByIndexErr(f.index); err == nil {
+						row[i] = cellOf(fv) // a nil embedded pointer leaves the cell empty
+					}
+				}
+			}
+			rows = append(rows, row)
+		}
+
+	case reflect.Map:
+		keys := map[string]bool{}
+		for _, item := range items {
+			if item.IsValid() && item.Kind() == reflect.Map {
+				for _, k := range item.MapKeys() {
+					keys[fmt.Sprint(k.Interface())] = true
+				}
+			}
+		}
+		for k := range keys {
+			cols = append(cols, k)
+		}
+		sort.Strings(cols)
+		for _, item := range items {
+			row := make([]string, len(cols))
+			if item.IsValid() && item.Kind() == reflect.Map {
+				iter := item.MapRange()
+				for iter.Next() {
+					i := slices.Index(cols, fmt.Sprint(iter.Key().Interface()))
+					row[i] = cellOf(iter.Value())
+				}
+			}
+			rows = append(rows, row)
+		}
+
+	default:
+		cols = []string{"value"}
+		for _, item := range items {
+			rows = append(rows, []string{cellOf(item)})
+		}
+	}
+	return
+}
+
+type columnField struct {
+	name  string
+	index []int
+}
+
+// structColumns returns the exported fields of t, named by their
+// json tags if present.
+func structColumns(t reflect.Type) (fields []columnField) {
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, columnField{name: name, index: f.Index})
+	}
+	return
+}
+
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.IsValid() && (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) {
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+var cellReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", "")
+
+func cellOf(rv reflect.Value) string {
+	if rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ""
+		}
+	}
+	if !rv.IsValid() || !rv.CanInterface() {
+		return ""
+	}
+	return cellReplacer.Replace(fmt.Sprint(rv.Interface()))
+}
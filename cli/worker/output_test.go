@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+type outputItem struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Port   int    `json:"port,omitempty"`
+	secret string
+}
+
+func TestWorkerS_Output(t *testing.T) {
+	ctx := context.Background()
+	items := []*outputItem{
+		{Name: "web", Status: "running", Port: 8080, secret: "x"},
+		{Name: "db", Status: "stopped"},
+	}
+
+	run := func(args ...string) (out string, err error) {
+		ta := newTestApp(t, ctx, func(s *cli.Config) {
+			s.OutputFormat = cli.OutputTable
+		})
+		ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			return cli.Output(ctx).Write(items)
+		})
+		err = ta.run(ctx, append([]string{"consul"}, args...)...)
+		return ta.sb.String(), err
+	}
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{nil, "NAME  STATUS   PORT\nweb   running  8080\ndb    stopped  0\n"},
+		{[]string{"--columns", "port,NAME"}, "PORT  NAME\n8080  web\n0     db\n"},
+		{[]string{"--output", "json"}, "[\n  {\n    \"name\": \"web\",\n    \"status\": \"running\",\n    \"port\": 8080\n  },\n  {\n    \"name\": \"db\",\n    \"status\": \"stopped\"\n  }\n]\n"},
+		{[]string{"--output=yaml"}, "- name: web\n  status: running\n  port: 8080\n- name: db\n  status: stopped\n"},
+		{[]string{"--template", "{{range .}}{{.Name}}:{{.Port}} {{end}}"}, "web:8080 db:0 \n"},
+	} {
+		out, err := run(c.args...)
+		if err != nil {
+			t.Fatalf("%v: %v", c.args, err)
+		}
+		if out != c.want {
+			t.Fatalf("%v: expecting\n%q\ngot\n%q", c.args, c.want, out)
+		}
+	}
+
+	if _, err := run("--columns", "unknown"); err == nil || !strings.Contains(err.Error(), `unknown column "unknown"`) {
+		t.Fatalf("expecting unknown column error, got %v", err)
+	}
+	if _, err := run("--output", "template"); err == nil {
+		t.Fatal("expecting an error for '--output template' without '--template'")
+	}
+}
+
+func TestWorkerS_OutputTitles(t *testing.T) {
+	ctx := context.Background()
+
+	rootFlags := func(ta *testApp, long string) (flags []*cli.Flag) {
+		for _, ff := range ta.ww.root.Cmd.(*cli.CmdS).Flags() {
+			if ff.Long == long {
+				flags = append(flags, ff)
+			}
+		}
+		return
+	}
+	opt := func(s *cli.Config) { s.OutputFormat = cli.OutputTable }
+
+	// the commands 'kv' and 'more' of the demo app take '-o'
+	for _, keepTaken := range []bool{true, false} {
+		ta := newTestApp(t, ctx, opt)
+		if !keepTaken {
+			ta.removeCommand("kv-store")
+			ta.removeCommand("more")
+		}
+		if err := ta.run(ctx, "--output", "json", "consul"); err != nil {
+			t.Fatal(err)
+		}
+		want := "o"
+		if keepTaken {
+			want = ""
+		}
+		if flags := rootFlags(ta, "output"); len(flags) != 1 || flags[0].Short != want {
+			t.Fatalf("expecting the builtin '--output' with short %q, got %v", want, flags)
+		}
+	}
+
+	// the app's own '--output' takes precedence
+	ta := newTestApp(t, ctx, opt)
+	own := &cli.Flag{BaseOpt: cli.BaseOpt{Long: "output", Short: "o"}}
+	own.SetDefaultValue("")
+	if err := ta.ww.root.Cmd.(*cli.CmdS).AddFlag(own); err != nil {
+		t.Fatal(err)
+	}
+	if err := ta.run(ctx, "consul"); err != nil {
+		t.Fatal(err)
+	}
+	if flags := rootFlags(ta, "output"); len(flags) != 1 || flags[0] != own || len(rootFlags(ta, "columns")) != 1 {
+		t.Fatalf("expecting the own '--output' of app kept only, got %v", flags)
+	}
+}
+
+func TestTabulate(t *testing.T) {
+	for _, c := range []struct {
+		v    any
+		cols []string
+		rows [][]string
+		ok   bool
+	}{
+		{[]string{"a", "b"}, []string{"value"}, [][]string{{"a"}, {"b"}}, true},
+		{map[string]any{"b": 2, "a": "x"}, []string{"a", "b"}, [][]string{{"x", "2"}}, true},
+		{[]any{map[string]int{"k": 1}, map[string]int{"j": 2}}, []string{"j", "k"}, [][]string{{"", "1"}, {"2", ""}}, true},
+		{outputItem{Name: "n"}, []string{"name", "status", "port"}, [][]string{{"n", "", "0"}}, true},
+		{"scalar", nil, nil, false},
+		{nil, nil, nil, false},
+	} {
+		cols, rows, ok := tabulate(c.v)
+		if ok != c.ok || strings.Join(cols, ",") != strings.Join(c.cols, ",") || len(rows) != len(c.rows) {
+			t.Fatalf("%v: unexpected %v, %v, %v", c.v, cols, rows, ok)
+		}
+		for i := range rows {
+			if strings.Join(rows[i], "|") != strings.Join(c.rows[i], "|") {
+				t.Fatalf("%v: unexpected row %d: %v", c.v, i, rows[i])
+			}
+		}
+	}
+}
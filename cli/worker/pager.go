@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/hedzr/is/term"
+
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+	"github.com/hedzr/cmdr/v2/pkg/pipe"
+)
+
+// pagerEnvVar returns the name of env-var to override the
+// pager, such as `APP_PAGER` (the prefix follows AutoEnvPrefix).
+func (w *workerS) pagerEnvVar() string {
+	prefix := w.AutoEnvPrefix
+	if prefix == "" {
+		prefix = "APP"
+	}
+	return prefix + "_PAGER"
+}
+
+// pagerCmd returns the pager command-line, or nil if paging is
+// disabled.
+//
+// The pager is chosen by (in priority order):
+//
+//   - command-line flag `--no-pager` disables it
+//   - env-var `APP_PAGER`, and an empty `APP_PAGER=` disables it
+//   - env-var `PAGER`
+//   - `less -R`
+func (w *workerS) pagerCmd() []string {
+	if w.noPager {
+		return nil
+	}
+	if v, ok := os.LookupEnv(w.pagerEnvVar()); ok {
+		return strings.Fields(v)
+	}
+	if v := os.Getenv("PAGER"); v != "" {
+		return strings.Fields(v)
+	}
+	return []string{"less", "-R"}
+}
+
+// shouldPage tests if the output to wr can be paged: it must be
+// the stdout which is a terminal. The output to HelpScreenWriter
+// is never paged.
+func (w *workerS) shouldPage(wr io.Writer) bool {
+	if f, ok := wr.(*os.File); !ok || f != os.Stdout {
+		return false
+	}
+	if len(w.pagerCmd()) == 0 {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// runPager pipes text to the stdin of the pager. It can be
+// replaced for testing.
+var runPager = func(text string, name string, args ...string) error {
+	return pipe.PipeToReader(func(stdout io.Writer) {
+		_, _ = io.WriteString(stdout, text)
+	}, name, args...)
+}
+
+// pageOut pipes text through the pager if it is taller than the
+// terminal. It returns false if text was not paged, so the caller
+// should print it by itself. An unknown height (rows <= 0) is
+// never paged.
+func (w *workerS) pageOut(ctx context.Context, text string, rows int) (paged bool) {
+	cmd := w.pagerCmd()
+	if len(cmd) == 0 || rows <= 0 || strings.Count(text, "\n") < rows {
+		return
+	}
+	err := runPager(text, cmd[0], cmd[1:]...)
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		logz.WarnContext(ctx, "[cmdr] pager not found", "pager", cmd, "err", err)
+		return
+	}
+	if err != nil {
+		logz.WarnContext(ctx, "[cmdr] pager failed", "pager", cmd, "err", err)
+	}
+	return true
+}
+
+// writePaged writes text to wr, or pipes it through the pager
+// if wr is a terminal and text is taller than it.
+func (s *helpPrinter) writePaged(ctx context.Context, wr HelpWriter, text string) {
+	if s.w != nil && s.w.shouldPage(wr) {
+		if _, rows := s.safeGetTermSize(); s.w.pageOut(ctx, text, rows) {
+			return
+		}
+	}
+	_, _ = wr.WriteString(text)
+}
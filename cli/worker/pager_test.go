@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_PagerCmd(t *testing.T) {
+	w := &workerS{Config: &cli.Config{}}
+	t.Setenv("APP_PAGER", "")
+	_ = os.Unsetenv("APP_PAGER")
+	t.Setenv("PAGER", "")
+	if cmd := w.pagerCmd(); !slices.Equal(cmd, []string{"less", "-R"}) {
+		t.Fatalf("expecting less -R, but got %v", cmd)
+	}
+	t.Setenv("PAGER", "more -d")
+	if cmd := w.pagerCmd(); !slices.Equal(cmd, []string{"more", "-d"}) {
+		t.Fatalf("expecting $PAGER, but got %v", cmd)
+	}
+	t.Setenv("APP_PAGER", "")
+	if cmd := w.pagerCmd(); len(cmd) != 0 {
+		t.Fatalf("expecting empty APP_PAGER disables paging, but got %v", cmd)
+	}
+	t.Setenv("APP_PAGER", "most")
+	w.noPager = true
+	if cmd := w.pagerCmd(); len(cmd) != 0 {
+		t.Fatalf("expecting --no-pager disables paging, but got %v", cmd)
+	}
+	if w.shouldPage(&strings.Builder{}) {
+		t.Fatal("the output to HelpScreenWriter should not be paged")
+	}
+}
+
+func TestWorkerS_PageOut(t *testing.T) {
+	ctx := context.Background()
+	var paged strings.Builder
+	var pager []string
+	runPagerDefault := runPager
+	defer func() { runPager = runPagerDefault }()
+	runPager = func(text string, name string, args ...string) error {
+		pager = append([]string{name}, args...)
+		paged.WriteString(text)
+		return nil
+	}
+	t.Setenv("APP_PAGER", "my-pager -x")
+
+	w := &workerS{Config: &cli.Config{}}
+	text := strings.Repeat("line\n", 10)
+	if w.pageOut(ctx, text, 20) {
+		t.Fatal("a short text should not be paged")
+	}
+	if w.pageOut(ctx, text, 0) {
+		t.Fatal("the text should not be paged if the terminal height is unknown")
+	}
+	if !w.pageOut(ctx, text, 5) {
+		t.Fatal("a long text should be paged")
+	}
+	if paged.String() != text || !slices.Equal(pager, []string{"my-pager", "-x"}) {
+		t.Fatalf("pager %v got %q", pager, paged.String())
+	}
+
+	runPager = runPagerDefault
+	t.Setenv("APP_PAGER", "no-such-pager-here")
+	if w.pageOut(ctx, text, 5) {
+		t.Fatal("expecting fallback if the pager is not found")
+	}
+}
@@ -214,7 +214,7 @@ func (w *workerS) matchCommand(ctx context.Context, pc *parseCtx) (err error) {
 			a = append(append(a[:pc.i], pcl...), a[pc.i:]...)
 			*pc.argsPtr = a
 		}
-		if nm := cc.PassThruNow(); nm {
+		if nm := cc.PassThruNow() || isCompleteRequest(cc); nm {
 			// pass-thru now
 			atomic.AddInt32(&pc.passThruMatched, 1)
 			logz.VerboseContext(ctx, "entering passThruMode since cmd requested", "i", pc.i, "cmd", cc)
@@ -259,7 +259,7 @@ func (w *workerS) preApplyEnvMatched(ctx context.Context, pc *parseCtx) (err err
 				var handled bool
 				ms := pc.addFlag(ff)
 				handled, err = ff.TryOnMatched(0, ms)
-				logz.DebugContext(ctx, "flag matched by envvar", "flg", ff, "envvar", evm.EnvVar, "value", evm.EnvValue)
+				logz.DebugContext(ctx, "flag matched by envvar", "flg", ff, "envvar", evm.EnvVar, "value", ff.MaskedValue(evm.EnvValue))
 				_ = handled
 			}
 		}
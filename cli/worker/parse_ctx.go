@@ -235,6 +235,15 @@ func (s *parseCtx) HasFlag(longTitle string, validator func(ff *cli.Flag, state
 	return
 }
 
+// DryRun reports whether '--dry-run' is set, by the command
+// line or its envvar.
+func (s *parseCtx) DryRun() bool {
+	if s == nil {
+		return false
+	}
+	return boolFlagSet(context.Background(), s.LastCmd(), "dry-run")
+}
+
 func (s *parseCtx) NoCandidateChildCommands() bool {
 	if s == nil {
 		return false
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hedzr/is/basics"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// publishPeripherals publishes the manager of peripherals
+// registered by cmdr.WithPeripherals() into the store, for the
+// lookup by cmdr.Peripheral() and cmdr.PeripheralT() in the
+// tasks and the action.
+func (w *workerS) publishPeripherals(ctx context.Context) {
+	m := w.Config.Peripherals
+	if m.Len() == 0 {
+		return
+	}
+	if st := w.Config.Store; st != nil {
+		st.WithinLoading(func() { st.Set(cli.PeripheralsStoreKey, m) })
+	}
+}
+
+// openPeripherals opens the peripherals registered by
+// cmdr.WithPeripherals() in the order of their dependencies,
+// each one has [cli.Config.OpenTimeout] to get ready.
+//
+// They are opened after TasksParsed and before TasksBeforeRun,
+// so the latter can use them.
+func (w *workerS) openPeripherals(ctx context.Context) (err error) {
+	return w.Config.Peripherals.Open(ctx, w.Config.OpenTimeout)
+}
+
+// closePeripherals closes the peripherals registered by
+// cmdr.WithPeripherals() in reverse order of opening, and then
+// the closers in basics.
+//
+// Each peripheral is given [cli.Config.CloseTimeout] at most,
+// and is abandoned once it timed out.
+func (w *workerS) closePeripherals(ctx context.Context) {
+	if w.Config != nil {
+		w.Config.Peripherals.Close(ctx, w.Config.CloseTimeout)
+	}
+	basics.Close()
+}
+
+// showHealth probes the peripherals for `~~health`, and returns
+// [cli.ErrUnhealthy] if any one isn't healthy.
+func (w *workerS) showHealth(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, args ...any) (err error) {
+	results := w.Config.Peripherals.Health(ctx, w.Config.OpenTimeout)
+
+	var sb strings.Builder
+	if strings.EqualFold(w.format, "json") {
+		data, e := json.MarshalIndent(results, "", "  ")
+		if e != nil {
+			return e
+		}
+		_, _ = sb.Write(data)
+		_, _ = sb.WriteString("\n")
+	} else {
+		for _, r := range results {
+			switch {
+			case r.Skipped:
+				_, _ = fmt.Fprintf(&sb, "  -  %s (no probe)\n", r.Name)
+			case r.Healthy:
+				_, _ = fmt.Fprintf(&sb, "  ok %s (%v)\n", r.Name, r.Elapsed)
+			default:
+				_, _ = fmt.Fprintf(&sb, "  !! %s: %s\n", r.Name, r.Error)
+			}
+		}
+		if len(results) == 0 {
+			_, _ = sb.WriteString("  no peripherals.\n")
+		}
+	}
+	_, _ = (&helpPrinter{w: w}).safeGetWriter().WriteString(sb.String())
+
+	var bad []string
+	for _, r := range results {
+		if !r.Healthy {
+			bad = append(bad, r.Name)
+		}
+	}
+	if len(bad) > 0 {
+		err = fmt.Errorf("%w: %s", cli.ErrUnhealthy, strings.Join(bad, ", "))
+	}
+	return
+}
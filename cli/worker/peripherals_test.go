@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+type probedPeripheral struct {
+	opened    bool
+	errHealth error
+}
+
+func (p *probedPeripheral) Open(ctx context.Context) error   { p.opened = true; return nil }
+func (p *probedPeripheral) Close()                           {}
+func (p *probedPeripheral) Health(ctx context.Context) error { return p.errHealth }
+
+func TestWorkerS_Peripherals(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(db, cache *probedPeripheral, args ...string) (ww *workerS, out string, opened bool, err error) {
+		ta := newTestApp(t, ctx, func(s *cli.Config) {
+			s.Peripherals = cli.NewPeripheralManager()
+			s.Peripherals.Add("db", db)
+			s.Peripherals.Add("cache", cache, "db")
+			s.TasksParsed = []cli.Task{func(ctx context.Context, cmd cli.Cmd, runner cli.Runner, extras ...any) (err error) {
+				if _, ok := runner.Store().MustGet(cli.PeripheralsStoreKey).(*cli.PeripheralManager); !ok {
+					err = errors.New("the peripherals are not published in TasksParsed")
+				}
+				return
+			}}
+			s.TasksBeforeRun = []cli.Task{func(ctx context.Context, cmd cli.Cmd, runner cli.Runner, extras ...any) (err error) {
+				if !db.opened || !cache.opened {
+					err = errors.New("the peripherals are not opened before TasksBeforeRun")
+				}
+				return
+			}}
+		})
+		ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			opened = db.opened && cache.opened
+			return
+		})
+		err = ta.run(ctx, args...)
+		return ta.ww, ta.sb.String(), opened, err
+	}
+
+	if _, _, opened, err := run(&probedPeripheral{}, &probedPeripheral{}, "consul"); err != nil || !opened {
+		t.Fatalf("expecting the peripherals opened before the action, opened=%v, err=%v", opened, err)
+	}
+
+	ww, out, _, err := run(&probedPeripheral{}, &probedPeripheral{errHealth: errors.New("refused")}, "~~health")
+	if !errors.Is(err, cli.ErrUnhealthy) || ww.SuggestRetCode() != cli.ExitUnavailable {
+		t.Fatalf("expecting ErrUnhealthy with exit code %d, got %v, %d", cli.ExitUnavailable, err, ww.SuggestRetCode())
+	}
+	if !strings.Contains(out, "ok db") || !strings.Contains(out, "!! cache: refused") {
+		t.Fatalf("unexpected health screen:\n%s", out)
+	}
+}
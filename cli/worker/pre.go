@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/hedzr/evendeep"
@@ -26,6 +27,8 @@ func (w *workerS) preProcess(ctx context.Context) (err error) {
 	dummyParseCtx := parseCtx{root: w.root, forceDefaultAction: w.ForceDefaultAction}
 
 	w.preEnvSet(ctx) // setup envvars: APP, APP_NAME, etc.
+	w.resolveProfile(ctx)
+	w.loadDotEnv(ctx) // load .env files before binding envvars to flags
 
 	var aliasMap map[string]*cli.CmdS
 	if aliasMap, err = w.linkCommands(ctx, w.root); err != nil {
@@ -40,6 +43,10 @@ func (w *workerS) preProcess(ctx context.Context) (err error) {
 		return
 	}
 
+	w.applyProfile(ctx) // overlay the profile entries in config files
+	w.applyLocale(ctx)  // the language of help screen and messages
+	w.publishPeripherals(ctx)
+
 	if w.invokeTasks(ctx, &dummyParseCtx, w.errs, w.TasksAfterLoader...) {
 		return
 	}
@@ -156,6 +163,8 @@ func (w *workerS) linkCommands(ctx context.Context, root *cli.RootCommand) (alia
 					}
 				}
 			}); err == nil {
+				// overlay 'profiles.NAME.*' before the flags bound.
+				w.applyProfile(ctx)
 				// commandsToStore will also evaluate envvars for the flags.
 				if err = w.commandsToStore(ctx, root); err == nil {
 					logz.VerboseContext(ctx, "linkCommands() - *RootCommand linked itself")
@@ -250,7 +259,7 @@ func (w *workerS) commandsToStoreR(ctx context.Context, root *cli.RootCommand, c
 				if w.envvarMatched == nil {
 					w.envvarMatched = make(map[*cli.Flag]EnvVarMatched)
 				}
-				logz.DebugContext(ctx, "envvar matched", "envvar", envvar, "value", data, "ff", ff)
+				logz.DebugContext(ctx, "envvar matched", "envvar", envvar, "value", ff.MaskedValue(data), "ff", ff)
 				w.envvarMatched[ff] = EnvVarMatched{envvar, value}
 				_ = handled
 			}
@@ -266,6 +275,11 @@ func (w *workerS) commandsToStoreR(ctx context.Context, root *cli.RootCommand, c
 		//    also bind the auto-binding env vars;
 		cx.WalkEverything(ctx, func(cc, pp cli.Cmd, ff *cli.Flag, cmdIndex, flgIndex, level int) {
 			if ff != nil {
+				if v, has := w.profileValueOf(ff); has {
+					// the envvars below still take precedence over the profile
+					ff.SetDefaultValue(v)
+					logz.VerboseContext(ctx, "profile value matched", "profile", w.profile, "ff", ff, "value", ff.MaskedValue(v))
+				}
 				if evs := ff.EnvVars(); len(evs) > 0 {
 					for _, ev := range evs {
 						if v, has := os.LookupEnv(ev); has {
@@ -356,7 +370,13 @@ func (w *workerS) loadLoaders(ctx context.Context) (err error) {
 		w.Loaders = append(w.Loaders, jsonLoader1, jsonLoader2)
 	}
 
-	for _, loader := range w.Loaders {
+	// The user config file `$CONFIG_DIR/appName.json`, which is
+	// written by `--save-config`, is loaded at last whatever the
+	// loaders are, so that the saved flags take precedence over
+	// the other config files.
+	loaders := append(slices.Clone(w.Loaders), &jsonLoaderS{filename: w.userConfigFile()})
+
+	for _, loader := range loaders {
 		if loader != nil {
 			if err = loader.Load(ctx, w.root.App()); err != nil {
 				if _, ok := loader.(*jsonLoaderS); !ok {
@@ -399,7 +419,13 @@ func (w *workerS) precheckLoaders(ctx context.Context) {
 
 // writeBackToLoaders implements write-back mechanism:
 // At the end of app terminated, the modified Store entries will be written back to "alternative config".
+//
+// The values of secret flags are never written back.
 func (w *workerS) writeBackToLoaders(ctx context.Context) (err error) {
+	return w.withoutSecrets(ctx, func() error { return w.writeBackToLoadersR(ctx) })
+}
+
+func (w *workerS) writeBackToLoadersR(ctx context.Context) (err error) {
 	for _, loader := range w.Loaders {
 		if loader != nil {
 			// see also (*conffileloader).Save(ctx) and file provider, and (*loadS).Save() and trySave()
@@ -36,6 +36,7 @@ type helpPrinter struct {
 	asManual        bool
 	lastFlagGroup   string
 	lastCmdGroup    string
+	th              *cli.Theme
 }
 
 const colLeftTabbedWidth = 46
@@ -70,6 +71,15 @@ func (s *helpPrinter) Print(ctx context.Context, pc cli.ParsedState, lastCmd cli
 
 	// fmt.Fprintln(wr, "Using helpWriter : ", wr, s.w.wrHelpScreen)
 
+	if s.w != nil && s.w.shouldPage(wr) {
+		// render into buffer, and pipe it through pager if it's
+		// taller than the terminal
+		var sb strings.Builder
+		s.PrintTo(ctx, &sb, pc, lastCmd, args...)
+		s.writePaged(ctx, wr, sb.String())
+		return
+	}
+
 	s.PrintTo(ctx, wr, pc, lastCmd, args...)
 }
 
@@ -91,7 +101,9 @@ func (s *helpPrinter) PrintTo(ctx context.Context, wr HelpWriter, pc cli.ParsedS
 
 	var painter Painter = s
 	if s.asManual {
-		painter = newManPainter()
+		mp := newManPainter()
+		mp.w = s.w
+		painter = mp
 	}
 
 	if s.treeMode {
@@ -110,6 +122,13 @@ func (s *helpPrinter) PrintTo(ctx context.Context, wr HelpWriter, pc cli.ParsedS
 		})
 		_, _ = wr.WriteString(sb.String())
 		// _, _ = wr.WriteString("\n")
+	} else if hp := s.customPainter(); hp != nil {
+		// help screen rendered by user-defined painter
+
+		screen := s.buildHelpScreen(ctx, pc, lastCmd, verboseCount)
+		if err := hp.Paint(ctx, wr, screen); err != nil {
+			logz.ErrorContext(ctx, "[cmdr] failed to paint help screen", "err", err)
+		}
 	} else {
 		// normal help screen
 
@@ -129,7 +148,7 @@ func (s *helpPrinter) PrintTo(ctx context.Context, wr HelpWriter, pc cli.ParsedS
 				parentIsDynamicLoading := p.IsDynamicCommandsLoading()
 				isFirstItem := index == 0 && (min(cnt, count) > 0 || parentIsDynamicLoading)
 				if isFirstItem {
-					painter.printCommandHeading(ctx, &sb, cc, "Commands")
+					painter.printCommandHeading(ctx, &sb, cc, cli.T(cli.MsgCommands))
 				} else {
 					// _, _ = sb.WriteString("\nCommands[")
 					// _, _ = sb.WriteString(strconv.Itoa(cnt))
@@ -149,19 +168,19 @@ func (s *helpPrinter) PrintTo(ctx context.Context, wr HelpWriter, pc cli.ParsedS
 			if isFirstItem {
 				if cc.OwnerCmd() == nil {
 					// _, _ = sb.WriteString("\nGlobal Flags:\n")
-					painter.printFlagHeading(ctx, &sb, cc, ff, "Global Flags")
+					painter.printFlagHeading(ctx, &sb, cc, ff, cli.T(cli.MsgGlobalFlags))
 					_, _ = sb.WriteString("\n")
 				} else if level == 0 {
 					// _, _ = sb.WriteString("\nFlags:\n")
-					painter.printFlagHeading(ctx, &sb, cc, ff, "Flags")
+					painter.printFlagHeading(ctx, &sb, cc, ff, cli.T(cli.MsgFlags))
 					_, _ = sb.WriteString("\n")
 				} else if level == 1 {
-					painter.printFlagHeading(ctx, &sb, cc, ff, "Parent Flags")
+					painter.printFlagHeading(ctx, &sb, cc, ff, cli.T(cli.MsgParentFlags))
 					_, _ = sb.WriteString("(")
 					_, _ = sb.WriteString(color.ToDim("%s", cc.String()))
 					_, _ = sb.WriteString("):\n")
 				} else {
-					painter.printFlagHeading(ctx, &sb, cc, ff, "Grandpa Flags")
+					painter.printFlagHeading(ctx, &sb, cc, ff, cli.T(cli.MsgGrandpaFlags))
 					_, _ = sb.WriteString("(")
 					_, _ = sb.WriteString(color.ToDim("%s", cc.String()))
 					_, _ = sb.WriteString("):\n")
@@ -170,6 +189,9 @@ func (s *helpPrinter) PrintTo(ctx context.Context, wr HelpWriter, pc cli.ParsedS
 			s.printFlag(ctx, pc, &sb, painter, &verboseCount, ff, ff.GroupHelpTitle(), groupIndex, 1, cols, tabbedW, walkCtx.Group)
 		}, walkCtx)
 
+		if !s.asManual {
+			s.printHelpTopics(ctx, &sb, lastCmd, pc, cols, tabbedW)
+		}
 		painter.printTailLine(ctx, &sb, lastCmd, pc, rows, cols, tabbedW)
 
 		_, _ = wr.WriteString(sb.String())
@@ -197,8 +219,23 @@ func (s *helpPrinter) PrintDebugScreenTo(ctx context.Context, wr HelpWriter, pc
 	// verboseCount := states.Env().CountOfVerbose()
 	// cols, rows := s.safeGetTermSize()
 
-	text := s.w.Store().Dump()
-	_, _ = sb.WriteString("\nStore:\n")
+	th := s.colors()
+	if profile := s.w.Profile(); profile != "" {
+		s.writeDebugHeading(&sb, "Profile")
+		_, _ = sb.WriteString(s.Translate(fmt.Sprintf("  <code>%s</code>\n", profile), th.Desc))
+	}
+
+	if entries := s.w.maskedDotEnvs(ctx); len(entries) > 0 {
+		s.writeDebugHeading(&sb, "Dotenv")
+		for _, e := range entries {
+			_, _ = sb.WriteString("  - ")
+			s.writeColored(&sb, th.EnvVars, e.Key)
+			_, _ = sb.WriteString(s.Translate(fmt.Sprintf(" = %q <dim>(%s)</dim>\n", e.Value, e.Source()), th.Desc))
+		}
+	}
+
+	text := s.w.maskedStore(ctx).Dump()
+	s.writeDebugHeading(&sb, "Store")
 	_, _ = sb.WriteString(text)
 	// _, _ = sb.WriteString("\n")
 
@@ -236,6 +273,15 @@ func (s *helpPrinter) safeGetTermSize() (cols, rows int) {
 	return
 }
 
+// customPainter returns the user-defined help screen painter,
+// it is ignored in generating manpages.
+func (s *helpPrinter) customPainter() cli.HelpPainter {
+	if s.w == nil || s.asManual {
+		return nil
+	}
+	return s.w.HelpPainter
+}
+
 type Painter interface {
 	printHeader(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int)
 	printUsage(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int)
@@ -332,8 +378,8 @@ func (s *helpPrinter) printUsage(ctx context.Context, sb *strings.Builder, cc cl
 	if tph := cc.TailPlaceHolder(); tph != "" {
 		tail = tph
 	}
-	line := fmt.Sprintf("$ <kbd>%s</kbd> %s [Options...]%s\n", appName, titles, tail)
-	_, _ = sb.WriteString("\nUsage:\n\n  ")
+	line := fmt.Sprintf("$ <kbd>%s</kbd> %s [%s]%s\n", appName, titles, cli.T(cli.MsgOptions), tail)
+	_, _ = sb.WriteString("\n" + cli.T(cli.MsgUsage) + ":\n\n  ")
 	// _, _ = sb.WriteString("\n")
 	_, _ = sb.WriteString(s.translate(pc, line, color.FgDefault))
 	_, _, _ = pc, cols, tabbedW
@@ -343,7 +389,7 @@ func (s *helpPrinter) printUsage(ctx context.Context, sb *strings.Builder, cc cl
 func (s *helpPrinter) printDesc(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int) {
 	desc := cc.DescLong()
 	if desc != "" {
-		_, _ = sb.WriteString("\nDescription:\n\n")
+		_, _ = sb.WriteString("\n" + cli.T(cli.MsgDescription) + ":\n\n")
 		desc = exec.StripLeftTabs(os.ExpandEnv(desc))
 		line := color.ToDim("%v", s.translate(pc, desc, color.FgDefault))
 		line = exec.LeftPad(line, 2)
@@ -366,7 +412,7 @@ func (s *helpPrinter) removeLastEmptyLines(lines []string) (lastLine int) {
 func (s *helpPrinter) printExamples(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int) {
 	examples := cc.Examples()
 	if examples != "" {
-		_, _ = sb.WriteString("\nExamples:\n\n")
+		_, _ = sb.WriteString("\n" + cli.T(cli.MsgExamples) + ":\n\n")
 		str := exec.StripLeftTabs(os.ExpandEnv(examples))
 
 		lines := strings.Split(str, "\n")
@@ -391,17 +437,24 @@ func (s *helpPrinter) printExamples(ctx context.Context, sb *strings.Builder, cc
 }
 
 func (s *helpPrinter) printNotes(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int) {
+	headed := false
+	heading := func() {
+		if !headed {
+			_, _ = sb.WriteString("\n" + cli.T(cli.MsgNotes) + ":\n\n")
+			headed = true
+		}
+	}
 	if root := cc.Root(); root.Cmd == cc && root.RedirectTo() != "" {
-		_, _ = sb.WriteString("\nNotes:\n\n")
+		heading()
 
-		str := exec.StripLeftTabs(fmt.Sprintf(`<i>Root Command was been redirected to Subcommand</i>: "<b>%s</b>"`, root.RedirectTo()))
+		str := exec.StripLeftTabs(cli.Tf(cli.MsgRedirectedRoot, root.RedirectTo()))
 		line := color.ToDim("%v", s.translate(pc, str, color.FgDefault))
 		line = exec.LeftPad(line, 2)
 		_, _ = sb.WriteString(line)
 
 		if m := root.RedirectToSet(); m != nil {
 			_, _ = sb.WriteString("\n")
-			_, _ = sb.WriteString("  All Redirected Commands: \n\n")
+			_, _ = sb.WriteString("  " + cli.T(cli.MsgAllRedirected) + ": \n\n")
 			for k, v := range m {
 				for to, froms := range v {
 					for _, from := range froms {
@@ -413,16 +466,16 @@ func (s *helpPrinter) printNotes(ctx context.Context, sb *strings.Builder, cc cl
 			// _, _ = sb.WriteString("\n")
 		}
 	} else if rt := cc.RedirectTo(); rt != "" {
-		_, _ = sb.WriteString("\nNotes:\n\n")
-		str := exec.StripLeftTabs(fmt.Sprintf(`<i>This Command was been redirected to</i>: "<b>%s</b>"`, rt))
+		heading()
+		str := exec.StripLeftTabs(cli.Tf(cli.MsgRedirectedTo, rt))
 		line := color.ToDim("%v", s.translate(pc, str, color.FgDefault))
 		line = exec.LeftPad(line, 2)
 		_, _ = sb.WriteString(line)
 	} else if cc1, ok := cc.(*cli.CmdS); ok {
 		for k, v := range root.RedirectToSet() {
 			if froms, ok := v[cc1]; ok {
-				_, _ = sb.WriteString("\nNotes:\n\n")
-				_, _ = sb.WriteString("  These commands redirect to here:\n\n")
+				heading()
+				_, _ = sb.WriteString("  " + cli.T(cli.MsgRedirectedFrom) + "\n\n")
 				for _, from := range froms {
 					_, _ = sb.WriteString("    ")
 					_, _ = sb.WriteString(s.translate(pc, fmt.Sprintf("<dim>%s --(<b>%s</b>)-> Me</dim>\n", from, k), color.FgDefault))
@@ -430,6 +483,7 @@ func (s *helpPrinter) printNotes(ctx context.Context, sb *strings.Builder, cc cl
 			}
 		}
 	}
+	s.printMetaNotes(ctx, sb, cc, pc, headed)
 	_, _, _ = pc, cols, tabbedW
 	_ = ctx
 }
@@ -441,7 +495,21 @@ func (s *helpPrinter) printEnv(ctx context.Context, sb *strings.Builder, wr Help
 		return
 	}
 
-	_, _ = sb.WriteString("\nEnvironments:\n")
+	if s.w != nil && s.w.envAll {
+		s.writeDebugHeading(sb, "Environment Variables Reference")
+		_ = printEnvVarsReference(sb, s.w.envVarsReference(ctx), s.w.format)
+	} else {
+		s.printEnvironments(sb)
+	}
+
+	_, _ = wr.WriteString(sb.String())
+	_, _ = wr.WriteString("\n")
+	_ = ctx
+}
+
+// printEnvironments prints the current environment variables.
+func (s *helpPrinter) printEnvironments(sb *strings.Builder) {
+	s.writeDebugHeading(sb, "Environments")
 	var keys, extras []string
 	b, m := false, map[string]string{}
 	for _, line := range os.Environ() {
@@ -473,10 +541,6 @@ func (s *helpPrinter) printEnv(ctx context.Context, sb *strings.Builder, wr Help
 		_, _ = sb.WriteString(color.ToDim("%s", m[key]))
 		_, _ = sb.WriteString("\n")
 	}
-
-	_, _ = wr.WriteString(sb.String())
-	_, _ = wr.WriteString("\n")
-	_ = ctx
 }
 
 func (s *helpPrinter) printRaw(ctx context.Context, sb *strings.Builder, wr HelpWriter, pc cli.ParsedState) {
@@ -486,7 +550,7 @@ func (s *helpPrinter) printRaw(ctx context.Context, sb *strings.Builder, wr Help
 		return
 	}
 
-	_, _ = sb.WriteString("\nRaw:\n")
+	s.writeDebugHeading(sb, "Raw")
 	_, _ = wr.WriteString(sb.String())
 	_, _ = wr.WriteString("\n")
 	_ = ctx
@@ -499,21 +563,42 @@ func (s *helpPrinter) printMore(ctx context.Context, sb *strings.Builder, wr Hel
 		return
 	}
 
-	_, _ = sb.WriteString("\nMore:\n")
+	s.writeDebugHeading(sb, "More")
 	_, _ = wr.WriteString(sb.String())
 	_, _ = wr.WriteString("\n")
 	_ = ctx
 }
 
+// writeDebugHeading writes the heading of a section of the debug
+// screen, in the GroupTitle color of theme.
+func (s *helpPrinter) writeDebugHeading(sb *strings.Builder, title string) {
+	_, _ = sb.WriteString("\n")
+	s.writeColored(sb, s.colors().GroupTitle, title+":")
+	_, _ = sb.WriteString("\n")
+}
+
+// writeColored writes text in clr, or in plain if the color is
+// disabled.
+func (s *helpPrinter) writeColored(sb *strings.Builder, clr color.Color, text string) {
+	if is.NoColorMode() {
+		_, _ = sb.WriteString(text)
+		return
+	}
+	s.WriteColor(sb, clr)
+	_, _ = sb.WriteString(text)
+	s.Reset(sb)
+}
+
 func (s *helpPrinter) printDebugMatches(ctx context.Context, sb *strings.Builder, wr HelpWriter, pc cli.ParsedState) {
+	th := s.colors()
 	if x := pc.MatchedCommands(); len(x) > 0 {
-		_, _ = sb.WriteString("\nMatched commands:\n")
+		s.writeDebugHeading(sb, "Matched commands")
 		for i, cc := range x {
-			_, _ = sb.WriteString(s.Translate(fmt.Sprintf("  - %d. <code>%s</code> | %v\n", i+1, cc.HitTitle(), cc), color.FgDefault))
+			_, _ = sb.WriteString(s.Translate(fmt.Sprintf("  - %d. <code>%s</code> | %v\n", i+1, cc.HitTitle(), cc), th.Desc))
 		}
 	}
 	if m := pc.MatchedFlags(); len(m) > 0 {
-		_, _ = sb.WriteString("\nMatched flags:\n")
+		s.writeDebugHeading(sb, "Matched flags")
 		i := 0
 		for ff, st := range m {
 			i++
@@ -531,24 +616,24 @@ func (s *helpPrinter) printDebugMatches(ctx context.Context, sb *strings.Builder
 			var extras strings.Builder
 			conf := ff.Store().BR()
 			_, _ = extras.WriteString(s.translate(pc, fmt.Sprintf(`<dim>final-value:</dim> %v`,
-				conf.MustGet(ff.LongTitle())), color.Reset))
+				ff.MaskedValue(conf.MustGet(ff.LongTitle()))), color.Reset))
 			_, _ = sb.WriteString(s.translate(pc,
 				fmt.Sprintf(
 					"  - %d. <code>%s</code> <dim>(+%v)</dim> %v <dim>/%v%v/</dim> | <dim>[owner: %v]</dim> | %s\n",
 					i, ff.GetHitStr(), ff.GetTriggeredTimes(), ff, short, tilde, ff.Owner().String(),
 					extras.String()),
-				color.FgDefault))
+				th.Desc))
 		}
 	}
 	if x := pc.PositionalArgs(); len(x) > 0 {
-		_, _ = sb.WriteString("\nPositional Args:\n")
+		s.writeDebugHeading(sb, "Positional Args")
 		for i, cc := range x {
-			_, _ = sb.WriteString(s.Translate(fmt.Sprintf("  - %d. <code>%s</code>\n", i+1, cc), color.FgDefault))
+			_, _ = sb.WriteString(s.Translate(fmt.Sprintf("  - %d. <code>%s</code>\n", i+1, cc), th.Desc))
 		}
 	}
 
 	if s.w != nil && s.w.actionsMatched != cli.ActionNone {
-		_, _ = sb.WriteString("\nACTIONS:\n")
+		s.writeDebugHeading(sb, "ACTIONS")
 		_, _ = sb.WriteString(s.translate(pc, fmt.Sprintf("<i>%s</i>", s.w.actionsMatched.String()), color.Reset))
 		_, _ = sb.WriteString("\n")
 	}
@@ -588,13 +673,14 @@ func (s *helpPrinter) printFlagHeading(ctx context.Context, sb *strings.Builder,
 }
 
 func (s *helpPrinter) printCommandGroupTitle(ctx context.Context, sb *strings.Builder, group string, indent int) {
+	th := s.colors()
 	_, _ = sb.WriteString(strings.Repeat("  ", indent))
 
 	noColor := is.NoColorMode()
 	colorful := !noColor
 	if colorful {
-		s.WriteColor(sb, CurrentGroupTitleColor)
-		s.WriteBgColor(sb, CurrentGroupTitleBgColor)
+		s.WriteColor(sb, th.GroupTitle)
+		s.WriteBgColor(sb, th.GroupTitleBg)
 	}
 	_, _ = sb.WriteString("[")
 	_, _ = sb.WriteString(group)
@@ -610,6 +696,7 @@ func (s *helpPrinter) printCommandRow(ctx context.Context, sb *strings.Builder,
 	indentSpaces, left, right, dep, depPlain string,
 	cols, tabbedW int, deprecated, dim bool,
 ) {
+	th := s.colors()
 	_, _ = sb.WriteString(indentSpaces)
 
 	noColor := is.NoColorMode()
@@ -627,9 +714,11 @@ func (s *helpPrinter) printCommandRow(ctx context.Context, sb *strings.Builder,
 		}
 		if deprecated {
 			s.WriteBgColor(sb, color.BgStrikeout)
-			s.WriteColor(sb, CurrentDescColor)
+			s.WriteColor(sb, th.Desc)
+		} else if dim {
+			s.WriteColor(sb, th.Hidden)
 		} else {
-			s.WriteColor(sb, CurrentTitleColor)
+			s.WriteColor(sb, th.Title)
 		}
 	}
 	_, _ = sb.WriteString(left)
@@ -638,7 +727,7 @@ func (s *helpPrinter) printCommandRow(ctx context.Context, sb *strings.Builder,
 	var printed int
 	if right != "" {
 		if colorful {
-			s.WriteColor(sb, CurrentDescColor)
+			s.WriteColor(sb, th.Desc)
 		}
 
 		rCols := cols - tabbedW
@@ -649,7 +738,7 @@ func (s *helpPrinter) printCommandRow(ctx context.Context, sb *strings.Builder,
 			for len(right) > rCols {
 				prt, right = right[:rCols], right[rCols:]
 				printleftpad(sb, ix > 0, tabbedW)
-				_, _ = sb.WriteString(trans(prt, s, CurrentDescColor, deprecated))
+				_, _ = sb.WriteString(trans(prt, s, th.Desc, deprecated))
 				ix++
 			}
 			if right != "" {
@@ -658,16 +747,16 @@ func (s *helpPrinter) printCommandRow(ctx context.Context, sb *strings.Builder,
 				} else {
 					split, printed = true, len(right)
 				}
-				_, _ = sb.WriteString(trans(right, s, CurrentDescColor, deprecated))
+				_, _ = sb.WriteString(trans(right, s, th.Desc, deprecated))
 			}
 		} else {
-			_, _ = sb.WriteString(trans(right, s, CurrentDescColor, deprecated))
+			_, _ = sb.WriteString(trans(right, s, th.Desc, deprecated))
 		}
 		// sb.WriteString(trans(right, CurrentDescColor))
 	} else {
 		if colorful {
-			s.WriteColor(sb, CurrentDescColor)
-			_, _ = sb.WriteString(trans("<i>(no desc)</i>", s, CurrentDescColor, deprecated))
+			s.WriteColor(sb, th.Desc)
+			_, _ = sb.WriteString(trans("<i>(no desc)</i>", s, th.Desc, deprecated))
 		} else {
 			_, _ = sb.WriteString("(no desc)")
 		}
@@ -695,9 +784,13 @@ func (s *helpPrinter) printCommand(ctx context.Context, sb *strings.Builder,
 	verboseCount *int, cc cli.Cmd,
 	group string, idx, level, cols, tabbedW int, grouped bool,
 ) (groupedInc int) {
+	th := s.colors()
 	if (cc.HiddenBR() && *verboseCount < 1) || (cc.VendorHiddenBR() && *verboseCount < 3) {
 		return
 	}
+	if s.w.unreleasedBR(cc) {
+		return
+	}
 
 	_ = idx
 	if grouped {
@@ -762,7 +855,7 @@ func (s *helpPrinter) printCommand(ctx context.Context, sb *strings.Builder,
 	left, right := fmt.Sprintf("%s%s", ttl, strings.Repeat(" ", w)), cc.Desc()
 	dep, depPlain := cc.DeprecatedHelpString(func(ss string, clr color.Color) string {
 		return trans(ss, s, clr, deprecated)
-	}, CurrentDeprecatedColor, CurrentDescColor)
+	}, th.Deprecated, th.Desc)
 
 	painter.printCommandRow(ctx, sb, cc, indentSpaces, left, right, dep, depPlain, cols, tabbedW, deprecated, dim)
 	_, _ = sb.WriteString("\n")
@@ -787,12 +880,13 @@ func (s *helpPrinter) printCommand(ctx context.Context, sb *strings.Builder,
 }
 
 func (s *helpPrinter) printFlagGroupTitle(ctx context.Context, sb *strings.Builder, group string, indent int) {
+	th := s.colors()
 	_, _ = sb.WriteString(strings.Repeat("  ", indent))
 	noColor := is.NoColorMode()
 	colorful := !noColor
 	if colorful {
-		s.WriteColor(sb, CurrentGroupTitleColor)
-		s.WriteBgColor(sb, CurrentGroupTitleBgColor)
+		s.WriteColor(sb, th.GroupTitle)
+		s.WriteBgColor(sb, th.GroupTitleBg)
 	}
 	_, _ = sb.WriteString("[")
 	_, _ = sb.WriteString(group)
@@ -808,6 +902,7 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 	indentSpaces, left, right, tg, def, defPlain, dep, depPlain, env, envPlain string,
 	cols, tabbedW int, deprecated, dim bool,
 ) {
+	th := s.colors()
 	_, _ = sb.WriteString(indentSpaces)
 
 	noColor := is.NoColorMode()
@@ -835,9 +930,11 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 		}
 		if deprecated {
 			s.WriteBgColor(sb, color.BgStrikeout)
-			s.WriteColor(sb, CurrentDescColor)
+			s.WriteColor(sb, th.Desc)
+		} else if dim {
+			s.WriteColor(sb, th.Hidden)
 		} else {
-			s.WriteColor(sb, CurrentTitleColor)
+			s.WriteColor(sb, th.Title)
 		}
 	}
 	_, _ = sb.WriteString(left)
@@ -846,7 +943,7 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 	if tg != "" {
 		// s.ColoredFast(&sb, CurrentFlagTitleColor, tg)
 		if colorful {
-			s.WriteColor(sb, CurrentFlagTitleColor)
+			s.WriteColor(sb, th.FlagTitle)
 		}
 		_, _ = sb.WriteString(tg)
 	}
@@ -862,7 +959,7 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 	rCols := cols - tabbedW
 	if right != "" {
 		if colorful {
-			s.WriteColor(sb, CurrentDescColor)
+			s.WriteColor(sb, th.Desc)
 		}
 
 		_, l, l1st := len(right), len(right)+len(defPlain)+len(depPlain)+len(envPlain), len(tg)
@@ -874,12 +971,12 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 				prt, right = right[:rCols-l1st], right[rCols-l1st:]
 				printleftpad(sb, ix > 0, tabbedW)
 				// aa = append(aa, prt)
-				_, _ = sb.WriteString(trans(prt, s, CurrentDescColor, deprecated))
+				_, _ = sb.WriteString(trans(prt, s, th.Desc, deprecated))
 				ix++
 				l1st = 0
 			}
 			if right != "" {
-				str := trans(right, s, CurrentDescColor, deprecated)
+				str := trans(right, s, th.Desc, deprecated)
 				if ix > 0 {
 					printleftpad(sb, ix > 0, tabbedW)
 				} else {
@@ -889,7 +986,7 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 			}
 		} else {
 			if colorful {
-				_, _ = sb.WriteString(trans(right, s, CurrentDescColor, deprecated))
+				_, _ = sb.WriteString(trans(right, s, th.Desc, deprecated))
 			} else {
 				_, _ = sb.WriteString(right)
 			}
@@ -902,8 +999,8 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 		// sb.WriteString(trans(right, CurrentDescColor))
 	} else {
 		if colorful {
-			s.WriteColor(sb, CurrentDescColor)
-			_, _ = sb.WriteString(trans("<i>(no desc)</i>", s, CurrentDescColor, deprecated))
+			s.WriteColor(sb, th.Desc)
+			_, _ = sb.WriteString(trans("<i>(no desc)</i>", s, th.Desc, deprecated))
 		} else {
 			_, _ = sb.WriteString("(no desc)")
 		}
@@ -942,7 +1039,7 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 
 	if ff.Required() {
 		str := "<kbd>REQUIRED</kbd>"
-		esc := s.Translate(str, CurrentFlagTitleColor)
+		esc := s.Translate(str, th.FlagTitle)
 		if split {
 			deflen := len(str)
 			printed += deflen
@@ -1013,7 +1110,7 @@ func (s *helpPrinter) printFlagRow(ctx context.Context, sb *strings.Builder,
 
 	if b := ff.Negatable(); b {
 		str := "<dim>(negatable)</dim>"
-		esc := s.Translate(str, CurrentDescColor)
+		esc := s.Translate(str, th.Desc)
 		_, _ = sb.Write([]byte(esc))
 	}
 
@@ -1031,9 +1128,13 @@ func (s *helpPrinter) printFlag(ctx context.Context, pc *cli.WalkBackwardsCtx,
 	verboseCount *int, ff *cli.Flag, group string,
 	idx, level, cols, tabbedW int, grouped bool,
 ) {
+	th := s.colors()
 	if (ff.HiddenBR() && *verboseCount < 1) || (ff.VendorHiddenBR() && *verboseCount < 3) {
 		return
 	}
+	if s.w.unreleasedFlag(ff) {
+		return
+	}
 
 	groupedInc := pc.LastCmdGroupInc
 	if s.treeMode {
@@ -1103,9 +1204,9 @@ func (s *helpPrinter) printFlag(ctx context.Context, pc *cli.WalkBackwardsCtx,
 	left, right := fmt.Sprintf("%s%s", ttl, strings.Repeat(" ", w)), ff.Desc()
 	tg := ff.ToggleGroupLeadHelpString()
 	trans1 := func(ss string, clr color.Color) string { return trans(ss, s, clr, deprecated) }
-	def, defPlain := ff.DefaultValueHelpString(trans1, CurrentDefaultValueColor, CurrentDescColor)
-	dep, depPlain := ff.DeprecatedHelpString(trans1, CurrentDeprecatedColor, CurrentDescColor)
-	env, envPlain := ff.EnvVarsHelpString(trans1, CurrentEnvVarsColor, CurrentDescColor)
+	def, defPlain := ff.DefaultValueHelpString(trans1, th.DefaultValue, th.Desc)
+	dep, depPlain := ff.DeprecatedHelpString(trans1, th.Deprecated, th.Desc)
+	env, envPlain := ff.EnvVarsHelpString(trans1, th.EnvVars, th.Desc)
 
 	painter.printFlagRow(ctx, sb, ff, indentSpaces, left, right, tg, def, defPlain, dep, depPlain, env, envPlain, cols, tabbedW, deprecated, dim)
 	_, _ = sb.WriteString("\n")
@@ -1133,7 +1234,7 @@ func (s *helpPrinter) printFlag(ctx context.Context, pc *cli.WalkBackwardsCtx,
 			_, _ = sb.WriteString("  ")
 		}
 		row := fmt.Sprintf("-<i>number</i> = --%s=<i>number</i>\n", ff.Title())
-		esc := s.Translate(row, CurrentFlagTitleColor)
+		esc := s.Translate(row, th.FlagTitle)
 		_, _ = sb.WriteString(esc)
 	}
 
@@ -1145,7 +1246,7 @@ func (s *helpPrinter) printFlag(ctx context.Context, pc *cli.WalkBackwardsCtx,
 				_, _ = sb.WriteString("  ")
 			}
 			row := fmt.Sprintf(" - <i>%s</i>, no-%s\n", it, it)
-			esc := s.Translate(row, CurrentFlagTitleColor)
+			esc := s.Translate(row, th.FlagTitle)
 			_, _ = sb.WriteString(esc)
 		}
 	}
@@ -1180,6 +1281,9 @@ var (
 	//
 	// currentHelpPainter Painter
 
+	// The Current*Color are the colors of help screen if no theme
+	// specified, see also cli.Theme and cmdr.WithTheme().
+
 	CurrentTitleColor     = color.FgDefault
 	CurrentFlagTitleColor = color.FgGreen
 
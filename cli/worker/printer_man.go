@@ -19,6 +19,7 @@ import (
 type (
 	manPainter struct {
 		writer io.Writer
+		w      *workerS
 		color.Translator
 		// buffer bufio.Writer
 	}
@@ -52,11 +53,11 @@ Auto generated by hedzr/cmdr
 .PP
 \fB{{.AppName}} generate manual [flags]\fP
 
-.SH DESCRIPTIONS
+.SH `+manHeading(cli.MsgDescription)+`
 .PP
 {{.LongDesc}}
 
-.SH EXAMPLES
+.SH `+manHeading(cli.MsgExamples)+`
 
 {{.ManExamples}}
 
@@ -69,6 +70,12 @@ Auto generated by hedzr/cmdr
 	}
 }
 
+// manHeading returns the localized section heading of manpage,
+// in upper case by convention.
+func manHeading(id string) string {
+	return strings.ToUpper(cli.T(id))
+}
+
 func getph1st(c cli.Cmd) string {
 	if s := c.TailPlaceHolder(); s != "" {
 		return s
@@ -105,7 +112,7 @@ func (s *manPainter) printUsage(ctx context.Context, sb *strings.Builder, cc cli
 
 func (s *manPainter) printDesc(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int) {
 	if !cc.OwnerIsNil() {
-		s.bufPrintf(sb, "\n.SH %s\n", "DESCRIPTION")
+		s.bufPrintf(sb, "\n.SH %s\n", manHeading(cli.MsgDescription))
 		s.bufPrintf(sb, ".PP\n%v\n", cc.DescLong())
 	}
 }
@@ -113,7 +120,7 @@ func (s *manPainter) printDesc(ctx context.Context, sb *strings.Builder, cc cli.
 func (s *manPainter) printExamples(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, cols, tabbedW int) {
 	if !cc.OwnerIsNil() {
 		if len(cc.Examples()) > 0 && cc.OwnerIsNotNil() {
-			s.bufPrintf(sb, "\n.SH %s\n", "EXAMPLES")
+			s.bufPrintf(sb, "\n.SH %s\n", manHeading(cli.MsgExamples))
 			s.bufPrintf(sb, ".PP\n%v\n", manExamples(cc.Examples(), cc.Root()))
 		}
 	}
@@ -126,8 +133,8 @@ func (s *manPainter) printNotes(ctx context.Context, sb *strings.Builder, cc cli
 
 		if root := cc.Root(); root.Cmd == cc && root.RedirectTo() != "" {
 			// _, _ = sb.WriteString("\nNotes:\n\n")
-			s.bufPrintf(sb, "\n.SH %s\n", "NOTES")
-			str := exec.StripLeftTabs(fmt.Sprintf(`<i>Root Command was been redirected to Subcommand</i>: "<b>%s</b>"`, root.RedirectTo()))
+			s.bufPrintf(sb, "\n.SH %s\n", manHeading(cli.MsgNotes))
+			str := exec.StripLeftTabs(cli.Tf(cli.MsgRedirectedRoot, root.RedirectTo()))
 			str = pc.Translate(str)
 			line := s.Translate(str, color.FgDefault)
 			line = exec.LeftPad(line, 2)
@@ -135,6 +142,12 @@ func (s *manPainter) printNotes(ctx context.Context, sb *strings.Builder, cc cli
 			_, _ = sb.WriteString(line)
 			_, _ = sb.WriteString("\n")
 		}
+		if lines := s.w.noteLines(cc); len(lines) > 0 {
+			s.bufPrintf(sb, "\n.SH %s\n", manHeading(cli.MsgNotes))
+			for _, ln := range lines {
+				_, _ = sb.WriteString(markdownishToRoff("- " + pc.Translate(ln)))
+			}
+		}
 		_, _, _ = pc, cols, tabbedW
 		_ = ctx
 	}
@@ -142,15 +155,44 @@ func (s *manPainter) printNotes(ctx context.Context, sb *strings.Builder, cc cli
 
 func (s *manPainter) printTailLine(ctx context.Context, sb *strings.Builder, cc cli.Cmd, pc cli.ParsedState, rows, cols, tabbedW int) {
 	root := cc.Root()
+	if cc.OwnerIsNil() && s.w != nil {
+		s.printEnvironment(ctx, sb)
+	}
 	s.bufPrintf(sb, `
-.SH SEE ALSO
+.SH %s
 .PP
 \fB%v(1)\fP
-
+`, manHeading(cli.MsgSeeAlso), root.AppName)
+	for _, dp := range s.w.seeAlsoPaths(cc) {
+		s.bufPrintf(sb, ".br\n\\fB%s(1)\\fP\n", seeAlsoPageName(root.AppName, dp))
+	}
+	if cc.OwnerIsNil() && s.w != nil {
+		for _, t := range s.w.helpTopics() {
+			s.bufPrintf(sb, ".br\n\\fB%s(7)\\fP\n", helpTopicPageName(root.AppName, t))
+		}
+	}
+	s.bufPrintf(sb, `
 .SH HISTORY
 .PP
 %v Auto generated by hedzr/cmdr
-`, root.AppName, time.Now().Format("02-Jan-2006")) // , time.RFC822Z
+`, time.Now().Format("02-Jan-2006")) // , time.RFC822Z
+}
+
+// printEnvironment prints the ENVIRONMENT section with all envvars
+// recognized by the app.
+func (s *manPainter) printEnvironment(ctx context.Context, sb *strings.Builder) {
+	refs := s.w.envVarsReference(ctx)
+	if len(refs) == 0 {
+		return
+	}
+	s.bufPrintf(sb, "\n.SH %s\n", manHeading(cli.MsgEnvironment))
+	for _, ref := range refs {
+		s.bufPrintf(sb, ".TP\n\\fB%s\\fP\n%s (%s", ref.Name, ref.BindsTo, ref.Type)
+		if ref.Default != "" {
+			s.bufPrintf(sb, ", default: %s", ref.Default)
+		}
+		s.bufPrintf(sb, ")\n")
+	}
 }
 
 // func (s *manPainter) printCommand(ctx context.Context, sb *strings.Builder, verboseCount *int, cc cli.Cmd, group string, idx, level, cols, tabbedW int, grouped bool) {
@@ -0,0 +1,127 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hedzr/store/radix"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/cli/atoa"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// Profile returns the active config profile name.
+//
+// The profile is chosen by (in priority order):
+//
+//   - command-line flag `--profile NAME`
+//   - env-var `APP_PROFILE` (the prefix follows AutoEnvPrefix)
+//   - [cli.Config.Profile], see also cmdr.WithProfile()
+func (w *workerS) Profile() string { return w.profile }
+
+func (w *workerS) profileEnvVar() string {
+	prefix := w.AutoEnvPrefix
+	if prefix == "" {
+		prefix = "APP"
+	}
+	return prefix + "_PROFILE"
+}
+
+// resolveProfile decides the active profile before the
+// command-line arguments are parsed, since the profile
+// entries must be overlaid onto the Store before the flags
+// bound.
+func (w *workerS) resolveProfile(ctx context.Context) {
+	w.profile = w.Config.Profile
+	if v, ok := os.LookupEnv(w.profileEnvVar()); ok && v != "" {
+		w.profile = v
+	}
+	if v, ok := scanProfileArg(w.args); ok {
+		w.profile = v
+	}
+	if w.profile != "" {
+		logz.VerboseContext(ctx, "active profile resolved", "profile", w.profile)
+	}
+}
+
+// scanProfileArg looks up `--profile NAME` or `--profile=NAME`
+// from the command-line. args[0] is the executable.
+func scanProfileArg(args []string) (name string, found bool) {
+	const flag = "--profile"
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if arg == flag {
+			if i+1 < len(args) {
+				name, found = args[i+1], true
+				i++
+			}
+		} else if strings.HasPrefix(arg, flag+"=") {
+			name, found = arg[len(flag)+1:], true
+		}
+	}
+	return
+}
+
+// profileKey returns the key path of an entry within the active profile.
+func (w *workerS) profileKey(key string) string {
+	return strings.Join([]string{cli.ProfilesStoreKey, w.profile, key}, ".")
+}
+
+// applyProfile overlays "profiles.NAME.*" onto the base Store.
+//
+// It is invoked twice: before the flags were bound into Store
+// (see commandsToStore), and after the external loaders
+// loaded, so the profiles in the config files can be applied
+// too.
+func (w *workerS) applyProfile(ctx context.Context) {
+	conf := w.Store()
+	if w.profile == "" || conf == nil {
+		return
+	}
+
+	from := conf.WithPrefix(cli.ProfilesStoreKey, w.profile).Prefix() + "."
+	var keys []string
+	var values []any
+	conf.Walk(from, func(path, fragment string, node radix.Node[any]) {
+		if !node.IsLeaf() || !strings.HasPrefix(path, from) || path == from {
+			return
+		}
+		keys, values = append(keys, path[len(from):]), append(values, node.Data())
+	})
+	if len(keys) == 0 {
+		logz.DebugContext(ctx, "profile has no entries", "profile", w.profile, "from", from)
+		return
+	}
+
+	// using store.WithinLoading to disable onSet callbacks and
+	// keep the profile entries from being written back.
+	conf.WithinLoading(func() {
+		for i, key := range keys {
+			_, _ = conf.Set(key, values[i])
+			logz.VerboseContext(ctx, "profile entry applied", "profile", w.profile, "key", key, "value", values[i])
+		}
+	})
+}
+
+// profileValueOf returns the value in the active profile for
+// the given flag, which will be used as the flag's default value.
+func (w *workerS) profileValueOf(ff *cli.Flag) (value any, has bool) {
+	conf := w.Store()
+	if w.profile == "" || conf == nil {
+		return
+	}
+	if value, has = conf.Get(w.profileKey(cli.CommandsStoreKey + "." + ff.GetDottedPath())); has {
+		if old := ff.DefaultValue(); old != nil {
+			if v, err := atoa.Parse(fmt.Sprint(value), old); err == nil {
+				value = v
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hedzr/store"
+)
+
+func TestScanProfileArg(t *testing.T) {
+	for i, c := range []struct {
+		args  []string
+		name  string
+		found bool
+	}{
+		{[]string{"app"}, "", false},
+		{[]string{"app", "--profile", "prod"}, "prod", true},
+		{[]string{"app", "server", "--profile=staging", "start"}, "staging", true},
+		{[]string{"app", "--profile"}, "", false},
+		{[]string{"app", "--", "--profile", "prod"}, "", false},
+		{[]string{"app", "--profile", "dev", "--profile=prod"}, "prod", true},
+	} {
+		name, found := scanProfileArg(c.args)
+		if name != c.name || found != c.found {
+			t.Fatalf("%d. scanProfileArg(%v) = %q, %v; want %q, %v", i, c.args, name, found, c.name, c.found)
+		}
+	}
+}
+
+func TestWorkerS_Profile(t *testing.T) {
+	ctx := context.Background()
+	app, ww := cleanApp(t, ctx, false)
+
+	conf := store.New()
+	conf.Set("logging.file", "/var/log/app.log")
+	conf.Set("profiles.prod.logging.file", "/var/log/app-prod.log")
+	conf.Set("profiles.prod.cmd.consul.data-center", "dc-prod")
+	ww.Config.Store = conf
+	ww.setArgs([]string{app.Name(), "--profile", "prod", "consul"})
+
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual("prod", ww.Profile())
+	assertEqual("/var/log/app-prod.log", ww.Store().MustString("logging.file"))
+	assertEqual("dc-prod", ww.Store().MustString("cmd.consul.data-center"))
+}
@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/cli/atoa"
+	"github.com/hedzr/cmdr/v2/internal/tool"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// maxPromptAttempts is how many times a missing flag is prompted
+// before giving up with ErrRequiredFlag.
+const maxPromptAttempts = 3
+
+// prompter asks the user for the values of missing flags. The
+// default one works on the terminal, the tests replace it.
+type prompter interface {
+	IsTerminal() bool
+	Writer() io.Writer
+	ReadLine(prompt string) (string, error)
+	ReadPassword(prompt string) (string, error)
+	Edit(editor string) (string, error)
+}
+
+type ttyPrompter struct {
+	rd *bufio.Reader
+}
+
+func (p *ttyPrompter) IsTerminal() bool  { return tool.StdinIsTerminal() }
+func (p *ttyPrompter) Writer() io.Writer { return os.Stderr }
+
+func (p *ttyPrompter) ReadLine(prompt string) (line string, err error) {
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	if p.rd == nil {
+		p.rd = bufio.NewReader(os.Stdin)
+	}
+	line, err = p.rd.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+func (p *ttyPrompter) ReadPassword(prompt string) (string, error) {
+	return tool.ReadPassword(prompt)
+}
+
+func (p *ttyPrompter) Edit(editor string) (text string, err error) {
+	var content []byte
+	if content, err = tool.LaunchEditorArgs(tool.SplitCommandString(editor), nil); err == nil {
+		text = string(content)
+	}
+	return
+}
+
+func (w *workerS) getPrompter() prompter {
+	if w.prompter == nil {
+		w.prompter = &ttyPrompter{}
+	}
+	return w.prompter
+}
+
+// canPrompt tests if the missing required flags can be prompted:
+// it's enabled by cmdr.WithPromptRequired(), not disabled by
+// '--no-input', and the stdin is a terminal.
+func (w *workerS) canPrompt() bool {
+	return w.Config.PromptRequired && !w.noInput &&
+		w.actionsMatched == cli.ActionNone && w.getPrompter().IsTerminal()
+}
+
+// promptFlag asks the value of ff, which is typed by the default
+// value of ff, chosen from its ValidArgs, and checked by its
+// Range. A secret flag is read without echo, and a flag with
+// ExternalEditor is edited by the editor if it's available.
+func (w *workerS) promptFlag(ctx context.Context, pc *parseCtx, ff *cli.Flag) (err error) {
+	p := w.getPrompter()
+	wr := p.Writer()
+
+	title := ff.GetTitleName()
+	if desc := ff.Desc(); desc != "" {
+		_, _ = fmt.Fprintf(wr, "%s: %s\n", title, desc)
+	}
+	choices := ff.ValidArgs()
+	if len(choices) > 0 {
+		_, _ = fmt.Fprintln(wr, cli.T(cli.MsgPromptChoose))
+		for i, c := range choices {
+			_, _ = fmt.Fprintf(wr, "  %d) %s\n", i+1, c)
+		}
+	}
+
+	prompt := title
+	if ph := ff.PlaceHolder(); ph != "" {
+		prompt += " (" + ph + ")"
+	}
+	prompt += ": "
+
+	var val any
+	for attempt := 0; attempt < maxPromptAttempts; attempt++ {
+		var text string
+		if text, err = w.readFlagText(p, ff, prompt); err != nil {
+			return
+		}
+		if val, err = parsePromptedValue(text, ff, choices); err == nil {
+			break
+		}
+		_, _ = fmt.Fprintln(wr, cli.Tf(cli.MsgPromptInvalid, text, err))
+	}
+	if err != nil {
+		return
+	}
+
+	logz.VerboseContext(ctx, "required flag prompted", "ff", ff)
+	ff.Owner().UpdateHitInfo(ff.LongTitle(), 1, ff)
+	ff.SetDefaultValue(val)
+	_, _ = ff.Store().Set(ff.Name(), val)
+	ff.WriteBoundValue(val)
+	if ms, has := pc.matchedFlags[ff]; has {
+		ms.Value = val
+	}
+	return
+}
+
+func (w *workerS) readFlagText(p prompter, ff *cli.Flag, prompt string) (text string, err error) {
+	if ff.Secret() {
+		return p.ReadPassword(prompt)
+	}
+	if env := ff.ExternalEditor(); env != "" {
+		if editor := os.Getenv(env); editor != "" {
+			if text, err = p.Edit(editor); err == nil {
+				text = strings.TrimRight(text, "\r\n")
+			}
+			return
+		}
+	}
+	return p.ReadLine(prompt)
+}
+
+var errEmptyInput = errors.New("empty input")
+
+// parsePromptedValue converts text to the type of the default
+// value of ff.
+func parsePromptedValue(text string, ff *cli.Flag, choices []string) (val any, err error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errEmptyInput
+	}
+	if len(choices) > 0 {
+		if i, e := strconv.Atoi(text); e == nil && i >= 1 && i <= len(choices) {
+			text = choices[i-1]
+		}
+		if !slices.Contains(choices, text) {
+			return nil, fmt.Errorf("%v", choices)
+		}
+	}
+
+	switch def := ff.DefaultValue().(type) {
+	case nil, string:
+		val = text
+	case bool:
+		switch strings.ToLower(text) {
+		case "y", "yes", "on":
+			val = true
+		case "n", "no", "off":
+			val = false
+		default:
+			val, err = strconv.ParseBool(text)
+		}
+	default:
+		val, err = atoa.Parse(text, def)
+	}
+	if err != nil {
+		return
+	}
+
+	if mn, mx := ff.Range(); mn != 0 || mx != 0 {
+		if n, ok := numberOf(val); ok && (n < float64(mn) || n > float64(mx)) {
+			return nil, errors.New(cli.Tf(cli.MsgPromptOutOfRange, mn, mx))
+		}
+	}
+	return
+}
+
+func numberOf(val any) (n float64, ok bool) {
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	}
+	return
+}
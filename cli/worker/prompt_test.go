@@ -0,0 +1,130 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+type fakePrompter struct {
+	tty     bool
+	answers []string
+	out     strings.Builder
+}
+
+func (p *fakePrompter) IsTerminal() bool  { return p.tty }
+func (p *fakePrompter) Writer() io.Writer { return &p.out }
+
+func (p *fakePrompter) ReadLine(prompt string) (line string, err error) {
+	p.out.WriteString(prompt)
+	if len(p.answers) == 0 {
+		return "", io.EOF
+	}
+	line, p.answers = p.answers[0], p.answers[1:]
+	return
+}
+
+func (p *fakePrompter) ReadPassword(prompt string) (string, error) { return p.ReadLine(prompt) }
+func (p *fakePrompter) Edit(editor string) (string, error)         { return p.ReadLine(editor) }
+
+func TestWorkerS_PromptRequired(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(p *fakePrompter, args ...string) (got any, err error) {
+		ta := newTestApp(t, ctx, func(s *cli.Config) {
+			s.PromptRequired = true
+		})
+		ta.ww.prompter = p
+
+		ff := ta.consul.FindFlag(ctx, "data-center", false)
+		ff.SetRequired(true)
+		ff.SetValidArgs("dc-1", "dc-2", "dc-3")
+		ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			got = ff.DefaultValue()
+			return
+		})
+		err = ta.run(ctx, append([]string{"consul"}, args...)...)
+		return
+	}
+
+	p := &fakePrompter{tty: true, answers: []string{"dc-9", "2"}}
+	if got, err := run(p); err != nil || got != "dc-2" {
+		t.Fatalf("expecting dc-2 prompted, got %v, %v", got, err)
+	}
+	if out := p.out.String(); !strings.Contains(out, "  3) dc-3\n") || !strings.Contains(out, `"dc-9"`) {
+		t.Fatalf("unexpected prompts:\n%s", out)
+	}
+
+	for _, c := range []struct {
+		p    *fakePrompter
+		args []string
+	}{
+		{&fakePrompter{tty: true, answers: []string{"dc-1"}}, []string{"--no-input"}},
+		{&fakePrompter{tty: false, answers: []string{"dc-1"}}, nil},
+		{&fakePrompter{tty: true, answers: []string{"", "x", "y"}}, nil}, // too many attempts
+		{&fakePrompter{tty: true}, nil},                                  // EOF
+	} {
+		if _, err := run(c.p, c.args...); !errors.Is(err, cli.ErrRequiredFlag) {
+			t.Fatalf("%v: expecting ErrRequiredFlag, got %v", c.args, err)
+		}
+	}
+}
+
+func TestWorkerS_PromptSecret(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(p *fakePrompter, args ...string) (got string, err error) {
+		ta := newTestApp(t, ctx)
+		ta.ww.prompter = p
+		ta.consul.FindFlag(ctx, "data-center", false).SetSecret(true)
+		if err = ta.run(ctx, append([]string{"consul"}, args...)...); err == nil {
+			got = ta.ww.Store().MustString("cmd.consul.data-center")
+		}
+		return
+	}
+
+	// the secret flag is given without value
+	p := &fakePrompter{tty: true, answers: []string{"s3cr3t"}}
+	if got, err := run(p, "--data-center", "--verbose"); err != nil || got != "s3cr3t" {
+		t.Fatalf("expecting the secret prompted, got %q, %v", got, err)
+	}
+	if out := p.out.String(); out != "data-center: " {
+		t.Fatalf("unexpected prompt %q", out)
+	}
+
+	for _, c := range []struct {
+		p    *fakePrompter
+		args []string
+	}{
+		{&fakePrompter{tty: true, answers: []string{"s3cr3t"}}, []string{"--data-center", "--no-input"}},
+		{&fakePrompter{tty: false, answers: []string{"s3cr3t"}}, []string{"--data-center", "--verbose"}},
+	} {
+		if got, err := run(c.p, c.args...); err != nil || got != "" {
+			t.Fatalf("%v: expecting no prompting, got %q, %v", c.args, got, err)
+		}
+	}
+}
+
+func TestParsePromptedValue(t *testing.T) {
+	ff := new(cli.Flag)
+	ff.SetDefaultValue(5)
+	ff.SetRange(1, 9)
+	if v, err := parsePromptedValue(" 7 ", ff, nil); err != nil || v != 7 {
+		t.Fatalf("expecting 7, got %v (%T), %v", v, v, err)
+	}
+	for _, text := range []string{"10", "0", "seven", ""} {
+		if _, err := parsePromptedValue(text, ff, nil); err == nil {
+			t.Fatalf("expecting an error for %q", text)
+		}
+	}
+
+	ff.SetDefaultValue(false)
+	ff.SetRange(0, 0)
+	if v, err := parsePromptedValue("y", ff, nil); err != nil || v != true {
+		t.Fatalf("expecting true, got %v, %v", v, err)
+	}
+}
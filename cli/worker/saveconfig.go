@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hedzr/is/dirs"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// userConfigFile returns the config file which `--save-config`
+// writes to.
+//
+// It is the file given by `--config FILE` if it is a json file,
+// or `$CONFIG_DIR/appName.json`.
+func (w *workerS) userConfigFile() string {
+	if w.configFile != "" && strings.EqualFold(filepath.Ext(w.configFile), ".json") {
+		return w.configFile
+	}
+	appName := w.Name()
+	configDir := os.Getenv("CONFIG_DIR")
+	if configDir == "" {
+		configDir = dirs.ConfigDir(appName)
+	}
+	return filepath.Join(configDir, appName+".json")
+}
+
+// savedFlags collects the flags which were set explicitly on
+// the command-line, and returns their values keyed by the
+// dotted path under `cmd.`.
+//
+// The builtin flags, the secret flags and the flags matched by
+// envvars only are excluded.
+func (w *workerS) savedFlags(pc *parseCtx) (values map[string]any) {
+	values = make(map[string]any)
+	for ff := range pc.matchedFlags {
+		if ff == nil || ff.Secret() || ff.SafeGroup() == cli.SysMgmtGroup {
+			continue
+		}
+		if evm, ok := w.envvarMatched[ff]; ok && ff.GetHitStr() == evm.EnvVar {
+			continue
+		}
+		val := ff.DefaultValue()
+		if d, ok := val.(time.Duration); ok {
+			val = d.String()
+		}
+		values[cli.CommandsStoreKey+"."+ff.GetDottedPath()] = val
+	}
+	return
+}
+
+// saveConfigFile implements `--save-config`: the flags set on
+// the command-line are merged into the user config file, the
+// other entries in the file are kept as is.
+func (w *workerS) saveConfigFile(ctx context.Context, pc *parseCtx) (err error) {
+	values := w.savedFlags(pc)
+	if len(values) == 0 {
+		logz.InfoContext(ctx, "[cmdr] nothing to save, no flags given on command-line")
+		return
+	}
+
+	filename := w.userConfigFile()
+	m := make(map[string]any)
+	var data []byte
+	if data, err = os.ReadFile(filename); err == nil {
+		if len(strings.TrimSpace(string(data))) > 0 {
+			if err = json.Unmarshal(data, &m); err != nil {
+				return
+			}
+		}
+	} else if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	} else {
+		return
+	}
+
+	for key, val := range values {
+		setMapValue(m, strings.Split(key, "."), val)
+	}
+
+	if data, err = json.MarshalIndent(m, "", "  "); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return
+	}
+	if err = os.WriteFile(filename, append(data, '\n'), 0o644); err == nil {
+		logz.InfoContext(ctx, "[cmdr] config saved", "file", filename, "count", len(values))
+	}
+	return
+}
+
+// setMapValue sets val into a nested map by the given path,
+// the intermediate maps will be created if necessary.
+func setMapValue(m map[string]any, parts []string, val any) {
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := m[part].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			m[part] = child
+		}
+		m = child
+	}
+	m[parts[len(parts)-1]] = val
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_SaveConfig(t *testing.T) {
+	ctx := context.Background()
+	configDir := t.TempDir()
+	t.Setenv("CONFIG_DIR", configDir)
+
+	app, ww := cleanApp(t, ctx, false)
+	ww.Config.Store = store.New()
+
+	fn := filepath.Join(configDir, app.Name()+".json")
+	if err := os.WriteFile(fn, []byte(`{"logging":{"file":"/var/log/app.log"},"cmd":{"consul":{"other":1}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ww.setArgs([]string{app.Name(), "consul", "--data-center", "dc-9", "--save-config"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err = json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual("/var/log/app.log", m["logging"].(map[string]any)["file"])
+	consul := m["cmd"].(map[string]any)["consul"].(map[string]any)
+	assertEqual("dc-9", consul["data-center"])
+	assertEqual(float64(1), consul["other"])
+	if _, ok := m["cmd"].(map[string]any)["save-config"]; ok {
+		t.Fatal("builtin flag '--save-config' should not be saved")
+	}
+}
+
+type nopLoader struct{ loaded bool }
+
+func (l *nopLoader) Load(ctx context.Context, app cli.App) (err error) {
+	l.loaded = true
+	return
+}
+
+func TestWorkerS_SaveConfigWithLoaders(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("CONFIG_DIR", t.TempDir())
+
+	app, ww := cleanApp(t, ctx, false)
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "consul", "--data-center", "dc-9", "--save-config"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the saved file is loaded even if the app has its own loaders
+	l := &nopLoader{}
+	app, ww = cleanApp(t, ctx, false, cli.WithExternalLoaders(l))
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "consul"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !l.loaded {
+		t.Fatal("expecting the loader of app invoked")
+	}
+	assertEqual("dc-9", ww.Store().MustString("cmd.consul.data-center"))
+}
@@ -0,0 +1,326 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hedzr/is/exec"
+	"github.com/hedzr/is/states"
+	"github.com/hedzr/is/term/color"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/text"
+)
+
+// searchHit is a command matched by `help --search`.
+type searchHit struct {
+	cmd     cli.Cmd
+	score   float64
+	snippet string
+	flags   []*cli.Flag
+}
+
+// the weights of the searchable fields of a command or flag.
+const (
+	searchWeightTitle    = 100.0
+	searchWeightAlias    = 80.0
+	searchWeightFlag     = 40.0
+	searchWeightDesc     = 30.0
+	searchWeightDescLong = 15.0
+	searchWeightExamples = 10.0
+
+	// searchFuzzyThreshold is the minimal Jaro-Winkler similarity
+	// of a fuzzy matched word.
+	searchFuzzyThreshold = 0.86
+)
+
+// searchTerms splits the user input into lowercased terms.
+func searchTerms(args ...string) (terms []string) {
+	for _, a := range args {
+		for _, t := range strings.Fields(strings.ToLower(a)) {
+			if t = strings.Trim(t, `"'`); t != "" {
+				terms = append(terms, t)
+			}
+		}
+	}
+	return
+}
+
+// searcher scores a text against the search terms.
+type searcher struct {
+	terms  []string
+	metric text.StringDistance
+}
+
+func newSearcher(terms []string) *searcher {
+	return &searcher{
+		terms:  terms,
+		metric: text.JaroWinklerDistance(text.JWWithThreshold(0.7)),
+	}
+}
+
+// score returns the weighted score of str. A substring hit
+// counts in full (twice for an exact hit), a fuzzy hit on a
+// word counts for its similarity, by half.
+func (s *searcher) score(str string, weight float64) (score float64) {
+	if str == "" {
+		return
+	}
+	str = strings.ToLower(str)
+	var words []string
+	for _, t := range s.terms {
+		switch {
+		case str == t:
+			score += weight * 2
+		case strings.Contains(str, t):
+			score += weight
+		case len([]rune(t)) >= 3:
+			if words == nil {
+				words = strings.FieldsFunc(str, func(r rune) bool {
+					return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+				})
+			}
+			best := 0.0
+			for _, w := range words {
+				d := float64(s.metric.Calc(t, w)) / text.StringMetricFactor
+				if d > best {
+					best = d
+				}
+			}
+			if best >= searchFuzzyThreshold {
+				score += weight * best / 2
+			}
+		}
+	}
+	return
+}
+
+// highlighter returns a regexp matches any of the terms.
+func (s *searcher) highlighter() *regexp.Regexp {
+	var parts []string
+	for _, t := range s.terms {
+		parts = append(parts, regexp.QuoteMeta(t))
+	}
+	return regexp.MustCompile(`(?i)(` + strings.Join(parts, "|") + `)`)
+}
+
+// snippet picks the first line of texts which contains a term,
+// cuts it around the hit and marks the terms.
+func (s *searcher) snippet(re *regexp.Regexp, texts ...string) string {
+	const width = 72
+	for _, t := range texts {
+		for _, ln := range strings.Split(t, "\n") {
+			ln = strings.TrimSpace(ln)
+			loc := re.FindStringIndex(ln)
+			if loc == nil {
+				continue
+			}
+			rs, start := []rune(ln), len([]rune(ln[:loc[0]]))
+			from, to := 0, len(rs)
+			if to > width {
+				if from = start - width/3; from < 0 {
+					from = 0
+				}
+				if to = from + width; to > len(rs) {
+					to, from = len(rs), max(len(rs)-width, 0)
+				}
+			}
+			str := string(rs[from:to])
+			if from > 0 {
+				str = "..." + str
+			}
+			if to < len(rs) {
+				str += "..."
+			}
+			return re.ReplaceAllString(str, "<mark>$1</mark>")
+		}
+	}
+	for _, t := range texts {
+		if t = strings.TrimSpace(t); t != "" {
+			ln, _, _ := strings.Cut(t, "\n")
+			return ln
+		}
+	}
+	return ""
+}
+
+// searchHelp searches the commands and flags under cc, and
+// returns the hits ranked by score.
+//
+// The titles, aliases, descriptions, long descriptions, examples
+// and flag names are scored by substring and fuzzy matching.
+func (w *workerS) searchHelp(ctx context.Context, pc cli.ParsedState, cc cli.Cmd, terms []string) (hits []*searchHit) {
+	if len(terms) == 0 || cc == nil {
+		return
+	}
+	expand := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		s = exec.StripLeftTabs(s)
+		if pc != nil {
+			s = pc.Translate(s)
+		}
+		return s
+	}
+
+	cx, ok := cc.(*cli.CmdS)
+	if !ok {
+		if rc, ok1 := cc.(*cli.RootCommand); ok1 {
+			cx, ok = rc.Cmd.(*cli.CmdS)
+		}
+	}
+	if !ok {
+		return
+	}
+
+	sr := newSearcher(terms)
+	re := sr.highlighter()
+	verbose := states.Env().CountOfVerbose() > 0
+
+	hist := make(map[cli.Cmd]*searchHit)
+	cx.WalkEverything(ctx, func(c, pp cli.Cmd, ff *cli.Flag, cmdIndex, flgIndex, level int) {
+		_, _, _, _ = pp, cmdIndex, flgIndex, level
+		if (!verbose && (c.HiddenBR() || c.VendorHiddenBR())) || w.unreleasedBR(c) {
+			return
+		}
+
+		if ff == nil {
+			desc, long, examples := expand(c.Desc()), expand(c.DescLong()), expand(c.Examples())
+			score := sr.score(c.Name(), searchWeightTitle)
+			for _, t := range c.GetTitleNamesArray() {
+				if t != c.Name() {
+					score += sr.score(t, searchWeightAlias)
+				}
+			}
+			score += sr.score(desc, searchWeightDesc)
+			score += sr.score(long, searchWeightDescLong)
+			score += sr.score(examples, searchWeightExamples)
+			hist[c] = &searchHit{cmd: c, score: score, snippet: sr.snippet(re, desc, long, examples)}
+			return
+		}
+
+		if (!verbose && (ff.HiddenBR() || ff.VendorHiddenBR())) || w.unreleased(ff.Since()) {
+			return
+		}
+		hit := hist[c]
+		if hit == nil {
+			return
+		}
+		var score float64
+		for _, t := range ff.GetTitleNamesArray() {
+			score += sr.score(t, searchWeightFlag)
+		}
+		score += sr.score(expand(ff.Desc()), searchWeightDesc/3)
+		if score > 0 {
+			hit.score += score
+			hit.flags = append(hit.flags, ff)
+		}
+	})
+
+	for _, hit := range hist {
+		if hit.score > 0 {
+			hits = append(hits, hit)
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].cmd.GetDottedPath() < hits[j].cmd.GetDottedPath()
+	})
+	return
+}
+
+// searchCommandPath returns the full path of a command, such as
+// `app server start`.
+func searchCommandPath(cc cli.Cmd) string {
+	path := cc.Root().AppName
+	if dp := cc.GetDottedPath(); dp != "" {
+		path += " " + strings.ReplaceAll(dp, ".", " ")
+	}
+	return path
+}
+
+// printSearchHits prints the results of `help --search`.
+func (s *helpPrinter) printSearchHits(wr io.Writer, terms []string, hits []*searchHit) {
+	if s.Translator == nil {
+		s.Translator = color.GetCPT()
+	}
+
+	var sb strings.Builder
+	th := s.colors()
+	if len(hits) == 0 {
+		_, _ = sb.WriteString(cli.Tf(cli.MsgSearchNoMatch, strings.Join(terms, " ")) + "\n")
+		_, _ = io.WriteString(wr, sb.String())
+		return
+	}
+
+	_, _ = sb.WriteString(cli.Tf(cli.MsgSearchResults, strings.Join(terms, " ")) + "\n\n")
+	for _, hit := range hits {
+		_, _ = sb.WriteString("  ")
+		_, _ = sb.WriteString(s.Translate(fmt.Sprintf("<b>%s</b>", searchCommandPath(hit.cmd)), th.Title))
+		_, _ = sb.WriteString("\n")
+		if hit.snippet != "" {
+			_, _ = sb.WriteString("      ")
+			_, _ = sb.WriteString(s.Translate(hit.snippet, th.Desc))
+			_, _ = sb.WriteString("\n")
+		}
+		if len(hit.flags) > 0 {
+			var names []string
+			for _, ff := range hit.flags {
+				names = append(names, ff.GetTitleZshFlagName())
+			}
+			_, _ = sb.WriteString("      ")
+			_, _ = sb.WriteString(s.Translate(cli.Tf(cli.MsgSearchFlags, strings.Join(names, ", ")), th.FlagTitle))
+			_, _ = sb.WriteString("\n")
+		}
+	}
+	_, _ = io.WriteString(wr, sb.String())
+}
+
+// showSearch is the reaction for `help --search TERMS`, and
+// `search TERMS` in the interactive help system.
+//
+// The args can be an io.Writer followed by the terms.
+func (w *workerS) showSearch(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, args ...any) (err error) {
+	hp := &helpPrinter{w: w}
+	var wr io.Writer = hp.safeGetWriter()
+	var words []string
+	for _, a := range args {
+		switch v := a.(type) {
+		case io.Writer:
+			wr = v
+		case string:
+			words = append(words, v)
+		case []string:
+			words = append(words, v...)
+		}
+	}
+	if len(words) == 0 {
+		words = append(words, w.searchTerms)
+	}
+
+	terms := searchTerms(words...)
+	if len(terms) == 0 {
+		return
+	}
+	var ps cli.ParsedState
+	if pc != nil {
+		ps = pc
+	}
+	hits := w.searchHelp(ctx, ps, lastCmd.Root().Cmd, terms)
+	if hw, ok := wr.(HelpWriter); ok && w.shouldPage(hw) {
+		var sb strings.Builder
+		hp.printSearchHits(&sb, terms, hits)
+		hp.writePaged(ctx, hw, sb.String())
+		return
+	}
+	hp.printSearchHits(wr, terms, hits)
+	return
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_Search(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(args ...string) string {
+		var sb strings.Builder
+		app, ww := cleanApp(t, ctx, false, withHelpScreenWriter(&sb), func(s *cli.Config) {})
+		ww.Config.Store = store.New()
+		ww.ForceDefaultAction = false
+		ww.setArgs(append([]string{app.Name()}, args...))
+		if err := ww.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return sb.String()
+	}
+
+	text := run("help", "--search", "datacenter")
+	t.Log(text)
+	if !strings.Contains(text, "consul") || !strings.Contains(text, "flags: --data-center") {
+		t.Fatalf("expecting consul command and its data-center flag, but got:\n%s", text)
+	}
+
+	// fuzzy
+	if text = run("help", "-s", "consull"); !strings.Contains(text, "consul") {
+		t.Fatalf("expecting consul command by fuzzy matching, but got:\n%s", text)
+	}
+
+	if text = run("help", "--search", "zzqqxx"); !strings.Contains(text, "No commands or flags matched") {
+		t.Fatalf("expecting nothing matched, but got:\n%s", text)
+	}
+
+	defer cli.SetLocale("")
+	if text = run("--lang", "de", "help", "--search", "zzqqxx"); !strings.Contains(text, "Keine Befehle oder Optionen passen") {
+		t.Fatalf("expecting the localized message, but got:\n%s", text)
+	}
+}
+
+func TestSearcher(t *testing.T) {
+	sr := newSearcher(searchTerms("Server  start"))
+	if s := sr.score("server", 10); s != 20 {
+		t.Fatalf("exact hit: expect 20 but got %v", s)
+	}
+	if s := sr.score("start the servers", 10); s != 20 {
+		t.Fatalf("substring hits: expect 20 but got %v", s)
+	}
+	if s := sr.score("nothing here", 10); s != 0 {
+		t.Fatalf("no hit: expect 0 but got %v", s)
+	}
+	if s := sr.score("serer", 10); s <= 0 || s >= 10 {
+		t.Fatalf("fuzzy hit: expect (0,10) but got %v", s)
+	}
+
+	re := sr.highlighter()
+	if got, expect := sr.snippet(re, "", "line 1\nto Start the Server\n"), "to <mark>Start</mark> the <mark>Server</mark>"; got != expect {
+		t.Fatalf("snippet: expect %q but got %q", expect, got)
+	}
+}
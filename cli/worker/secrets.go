@@ -0,0 +1,143 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// secretResolvers merges the builtin resolvers with the ones
+// registered by cmdr.WithSecretResolver.
+func (w *workerS) secretResolvers() map[string]cli.SecretResolver {
+	resolvers := cli.DefaultSecretResolvers()
+	for k, r := range w.SecretResolvers {
+		resolvers[k] = r
+	}
+	return resolvers
+}
+
+// walkSecretFlags walks all secret flags in the whole command tree.
+func (w *workerS) walkSecretFlags(ctx context.Context, cb func(ff *cli.Flag)) {
+	if w.root == nil {
+		return
+	}
+	if cx, ok := w.root.Cmd.(*cli.CmdS); ok {
+		cx.WalkEverything(ctx, func(cc, pp cli.Cmd, ff *cli.Flag, cmdIndex, flgIndex, level int) {
+			if ff != nil && ff.Secret() {
+				cb(ff)
+			}
+			_, _, _, _, _ = cc, pp, cmdIndex, flgIndex, level
+		})
+	}
+}
+
+// resolveSecretFlags resolves the indirect values (such as
+// `file:/run/secrets/db-pass`) of the secret flags, and prompts
+// the values without echo if the flags are given without values,
+// stdin is a terminal and '--no-input' isn't given.
+func (w *workerS) resolveSecretFlags(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd) (err error) {
+	wbc := &cli.WalkBackwardsCtx{
+		Group: true,
+		Sort:  false,
+	}
+	var resolvers map[string]cli.SecretResolver
+	lastCmd.WalkBackwardsCtx(ctx, func(ctx context.Context, wc *cli.WalkBackwardsCtx, cc cli.Cmd, ff *cli.Flag, index, groupIndex, count, level int) {
+		if err != nil || ff == nil || !ff.Secret() {
+			return
+		}
+		_, _, _, _, _, _ = wc, cc, index, groupIndex, count, level
+
+		val, ok := ff.DefaultValue().(string)
+		if !ok {
+			return
+		}
+
+		if val != "" {
+			if resolvers == nil {
+				resolvers = w.secretResolvers()
+			}
+			var resolved bool
+			if val, resolved, err = cli.ResolveSecret(ctx, val, resolvers); err != nil {
+				err = cli.LocalizeError(cli.MsgErrSecretResolving, cli.ErrSecretResolving, ff, err)
+				return
+			} else if !resolved {
+				return
+			}
+			logz.VerboseContext(ctx, "secret flag resolved", "ff", ff)
+		} else if ff.GetTriggeredTimes() > 0 {
+			// the flag is given without value. A missing required
+			// one is left to checkRequiredFlags.
+			p := w.getPrompter()
+			if w.actionsMatched != cli.ActionNone || w.noInput || !p.IsTerminal() {
+				return
+			}
+			if val, err = p.ReadPassword(fmt.Sprintf("%s: ", ff.GetTitleName())); err != nil {
+				return
+			}
+		} else {
+			return
+		}
+
+		ff.SetDefaultValue(val)
+		_, _ = ff.Store().Set(ff.Name(), val)
+		ff.WriteBoundValue(val)
+		if ms, has := pc.matchedFlags[ff]; has {
+			ms.Value = val
+		}
+	}, wbc)
+	return
+}
+
+// maskedStore returns a copy of the application Store, in which
+// the values of secret flags are masked.
+func (w *workerS) maskedStore(ctx context.Context) (conf store.Store) {
+	conf = w.Store()
+	dup := false
+	w.walkSecretFlags(ctx, func(ff *cli.Flag) {
+		key := cli.CommandsStoreKey + "." + ff.GetDottedPath()
+		if v, ok := conf.Get(key); ok && ff.MaskedValue(v) != v {
+			if !dup {
+				conf, dup = conf.Dup(), true
+			}
+			_, _ = conf.Set(key, cli.SecretMask)
+		}
+	})
+	return
+}
+
+// withoutSecrets removes the secret flags from the application
+// Store temporarily while running fn, so that they will never
+// be written back to the config files.
+func (w *workerS) withoutSecrets(ctx context.Context, fn func() error) (err error) {
+	conf := w.Store()
+	if conf == nil {
+		return fn()
+	}
+
+	saved := make(map[string]any)
+	w.walkSecretFlags(ctx, func(ff *cli.Flag) {
+		key := cli.CommandsStoreKey + "." + ff.GetDottedPath()
+		if v, ok := conf.Get(key); ok {
+			saved[key] = v
+		}
+	})
+	if len(saved) == 0 {
+		return fn()
+	}
+
+	conf.WithinLoading(func() {
+		for key := range saved {
+			conf.Remove(key)
+		}
+	})
+	defer conf.WithinLoading(func() {
+		for key, v := range saved {
+			_, _ = conf.Set(key, v)
+		}
+	})
+	return fn()
+}
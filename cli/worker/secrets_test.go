@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_SecretFlag(t *testing.T) {
+	ctx := context.Background()
+	app, ww := cleanApp(t, ctx, false)
+	ww.Config.Store = store.New()
+
+	fn := filepath.Join(t.TempDir(), "db-pass")
+	if err := os.WriteFile(fn, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, ff := cli.DottedPathToCommandOrFlag1("consul.data-center", ww.root)
+	if ff == nil {
+		t.Fatal("flag 'consul.data-center' not found")
+	}
+	ff.SetSecret(true)
+
+	ww.setArgs([]string{app.Name(), "consul", "--data-center", "file:" + fn})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual("s3cr3t", ww.Store().MustString("cmd.consul.data-center"))
+	assertEqual(cli.SecretMask, ww.maskedStore(ctx).MustString("cmd.consul.data-center"))
+	// the original Store is untouched
+	assertEqual("s3cr3t", ww.Store().MustString("cmd.consul.data-center"))
+	assertEqual(cli.SecretMask, ff.MaskedValue(ff.DefaultValue()))
+}
+
+func TestWorkerS_SecretFlagValues(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(args ...string) (got string, err error) {
+		app, ww := cleanApp(t, ctx, false)
+		ww.Config.Store = store.New()
+		_, ff := cli.DottedPathToCommandOrFlag1("consul.data-center", ww.root)
+		if ff == nil {
+			t.Fatal("flag 'consul.data-center' not found")
+		}
+		ff.SetSecret(true)
+		ww.setArgs(append([]string{app.Name(), "consul"}, args...))
+		if err = ww.Run(ctx); err == nil {
+			got = ww.Store().MustString("cmd.consul.data-center")
+		}
+		return
+	}
+
+	// a password may start with '-' or '~'
+	for _, pass := range []string{"-abc", "~x1", "--not-a-flag"} {
+		if got, err := run("--data-center", pass); err != nil || got != pass {
+			t.Fatalf("expecting %q, got %q, %v", pass, got, err)
+		}
+	}
+
+	// but a known flag isn't taken as the value
+	if got, err := run("--data-center", "--verbose"); err != nil || got != "" {
+		t.Fatalf("expecting the value missed, got %q, %v", got, err)
+	}
+
+	t.Setenv("CMDR_TEST_SECRET", "")
+	if got, err := run("--data-center", "env:CMDR_TEST_SECRET"); err != nil || got != "" {
+		t.Fatalf("expecting an empty secret, got %q, %v", got, err)
+	}
+	if _, err := run("--data-center", "env:CMDR_TEST_SECRET_UNSET"); !errors.Is(err, cli.ErrSecretResolving) {
+		t.Fatalf("expecting ErrSecretResolving, got %v", err)
+	}
+}
@@ -0,0 +1,452 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedzr/is/dirs"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+const (
+	defaultStopTimeout = 10 * time.Second
+	daemonStartupWait  = 3 * time.Second // how long `server start` waits for the pidfile
+	daemonPollInterval = 50 * time.Millisecond
+)
+
+// hasBuiltinServer tests if the builtin command group `server`
+// is enabled: [cli.Config.Server] is set, and app has no command
+// `server` of its own, which takes precedence.
+func (w *workerS) hasBuiltinServer(p *cli.CmdS) bool {
+	if w.Config.Server == nil {
+		return false
+	}
+	for _, cc := range p.SubCommands() {
+		if cc.Name() == "server" {
+			return false
+		}
+	}
+	return true
+}
+
+// builtinServer adds the command group `server` if
+// hasBuiltinServer.
+//
+// The generator `generate systemd` is added by builtinGenerators
+// in the same condition.
+func (w *workerS) builtinServer(app cli.App, p *cli.CmdS) {
+	if !w.hasBuiltinServer(p) {
+		if w.Config.Server != nil {
+			logz.Warn("[cmdr] builtin command 'server' is ignored since app has its own one")
+		}
+		return
+	}
+
+	app.NewCmdFrom(p, func(bb cli.CommandBuilder) {
+		bb.Titles("server", "", "daemon", "svc").
+			Description("Manage the daemon of this app", `
+The daemon runs in background with its pidfile and log file, or
+in foreground by '--foreground' under a service manager, such as
+systemd.
+			`).
+			Examples(`
+$ {{.AppName}} server start
+	start the daemon in background, the output goes to the log file
+$ {{.AppName}} server start --foreground
+	run in foreground, for systemd and docker
+$ {{.AppName}} server status
+	the exit code is 3 if the daemon is not running
+$ {{.AppName}} generate systemd > /etc/systemd/system/{{.AppName}}.service
+	install as a systemd service
+			`)
+
+		bb.Cmd("start", "", "run").
+			Description("Start the daemon").
+			OnAction(w.serverStart).
+			With(func(b cli.CommandBuilder) {
+				b.Flg("foreground", "f").
+					Default(false).
+					Description("Run in foreground, without detaching").
+					Build()
+			})
+
+		bb.Cmd("stop", "", "shutdown").
+			Description("Stop the daemon by SIGTERM").
+			OnAction(w.serverStop).
+			With(func(b cli.CommandBuilder) {
+				b.Flg("force", "").
+					Default(false).
+					Description("Kill the daemon by SIGKILL if it doesn't stop in time").
+					Build()
+			})
+
+		bb.Cmd("restart", "").
+			Description("Stop and start the daemon").
+			OnAction(w.serverRestart).
+			Build()
+
+		bb.Cmd("status", "", "st").
+			Description("Show the running status of the daemon").
+			OnAction(w.serverStatus).
+			Build()
+
+		bb.Cmd("reload", "", "hup").
+			Description("Ask the daemon to reload by SIGHUP").
+			OnAction(w.serverReload).
+			Build()
+	})
+}
+
+func (w *workerS) pidFile() string {
+	if f := w.Config.Server.PidFile; f != "" {
+		return f
+	}
+	return filepath.Join(dirs.VarRunDir(w.root.AppName), w.root.AppName+".pid")
+}
+
+func (w *workerS) logFile() string {
+	if f := w.Config.Server.LogFile; f != "" {
+		return f
+	}
+	return filepath.Join(dirs.VarLogDir(w.root.AppName), w.root.AppName+".log")
+}
+
+func (w *workerS) stopTimeout() time.Duration {
+	if d := w.Config.Server.StopTimeout; d > 0 {
+		return d
+	}
+	return defaultStopTimeout
+}
+
+// readPid returns the pid recorded in pidfile, and whether the
+// process is alive.
+func readPid(pidFile string) (pid int, alive bool) {
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		return
+	}
+	if pid, err = strconv.Atoi(strings.TrimSpace(string(data))); err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, processAlive(pid)
+}
+
+func writePid(pidFile string, pid int) (err error) {
+	if err = os.MkdirAll(filepath.Dir(pidFile), 0o755); err == nil {
+		err = os.WriteFile(pidFile, []byte(strconv.Itoa(pid)+"\n"), 0o644)
+	}
+	return
+}
+
+// removePid removes pidfile if it records pid.
+func removePid(pidFile string, pid int) {
+	if data, err := os.ReadFile(pidFile); err == nil && strings.TrimSpace(string(data)) == strconv.Itoa(pid) {
+		_ = os.Remove(pidFile)
+	}
+}
+
+// serverStart, serverStop, serverRestart and serverReload print
+// what would be done in '--dry-run' mode.
+func (w *workerS) serverStart(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	if cmd.Store().MustBool("foreground") {
+		if w.dryRun() {
+			return w.printDryRun("serve in foreground, pidfile: " + w.pidFile())
+		}
+		return w.serveForeground(ctx, cmd, args)
+	}
+	return w.startBackground(ctx, cmd)
+}
+
+// serveForeground records the pidfile and runs
+// [cli.ServerOptions.Serve] until SIGINT or SIGTERM.
+func (w *workerS) serveForeground(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	pidFile, pid := w.pidFile(), os.Getpid()
+	if running, alive := readPid(pidFile); alive && running != pid {
+		return fmt.Errorf("%w, pid %d", cli.ErrAlreadyRunning, running)
+	}
+	if err = writePid(pidFile, pid); err != nil {
+		return
+	}
+	defer removePid(pidFile, pid)
+
+	if !w.Config.HandleSignals {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		defer w.listenSignals(ctx, cancel)()
+	}
+	logz.InfoContext(ctx, "[cmdr] daemon started", "pid", pid, "pidfile", pidFile)
+
+	if serve := w.Config.Server.Serve; serve != nil {
+		err = serve(ctx, cmd, args)
+	} else {
+		<-ctx.Done()
+	}
+	if err == nil {
+		w.signalCode.Store(0) // stopped by SIGTERM gracefully
+	}
+	logz.InfoContext(ctx, "[cmdr] daemon stopped", "pid", pid, "err", err)
+	return
+}
+
+// daemonFlags are the global flags passed to the background
+// daemon. The others, such as '--timeout', '--dry-run' and
+// '--yes', are dropped.
+var daemonFlags = []string{"config", "profile", "verbose", "quiet", "debug"}
+
+// daemonArgs returns the command line of the background daemon:
+// the given global flags in daemonFlags, and the command path of
+// 'server start --foreground', the hit subcommand of 'server'
+// (such as 'restart') is replaced by 'start'.
+func (w *workerS) daemonArgs(cmd cli.Cmd) (args []string) {
+	if pc, ok := w.parsingCtx.(*parseCtx); ok {
+		for _, long := range daemonFlags {
+			for ff, ms := range pc.matchedFlags {
+				if ff.Long == long && ff.Owner() != nil && ff.Owner().OwnerIsNil() {
+					args = append(args, daemonFlagArgs(ff, ms)...)
+				}
+			}
+		}
+	}
+
+	var path []string
+	for c := cmd.OwnerCmd(); c != nil && !c.OwnerIsNil(); c = c.OwnerCmd() {
+		path = append([]string{c.Name()}, path...)
+	}
+	if len(path) == 0 {
+		path = []string{"server"}
+	}
+	return append(append(args, path...), "start", "--foreground")
+}
+
+// daemonFlagArgs returns the args of a matched flag, a bool flag
+// is repeated by its hit times, such as '-vv'.
+func daemonFlagArgs(ff *cli.Flag, ms *cli.MatchState) (args []string) {
+	switch v := ms.Value.(type) {
+	case bool:
+		for i := 0; v && i < max(ms.HitTimes, 1); i++ {
+			args = append(args, "--"+ff.Long)
+		}
+	default:
+		args = append(args, fmt.Sprintf("--%s=%v", ff.Long, v))
+	}
+	return
+}
+
+// startBackground re-executes the app as 'server start
+// --foreground' in a new session, with the output redirected to
+// the log file, and waits for its pidfile.
+func (w *workerS) startBackground(ctx context.Context, cmd cli.Cmd) (err error) {
+	pidFile, logFile := w.pidFile(), w.logFile()
+	dryRun := w.dryRun()
+	// in '--dry-run' mode, the daemon is still alive after
+	// stopping by 'restart'.
+	if pid, alive := readPid(pidFile); alive && !(dryRun && cmd.Name() == "restart") {
+		return fmt.Errorf("%w, pid %d", cli.ErrAlreadyRunning, pid)
+	}
+
+	var exe string
+	if exe, err = os.Executable(); err != nil {
+		return
+	}
+	if dryRun {
+		return w.printDryRun(fmt.Sprintf("start %s in background, log: %s", exe, logFile))
+	}
+	if err = os.MkdirAll(filepath.Dir(logFile), 0o755); err != nil {
+		return
+	}
+	var f *os.File
+	if f, err = os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+		return
+	}
+	defer f.Close()
+
+	c := osexec.Command(exe, w.daemonArgs(cmd)...)
+	c.Stdout, c.Stderr = f, f
+	detach(c)
+	if err = c.Start(); err != nil {
+		return
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- c.Wait() }()
+
+	wr := (&helpPrinter{w: w}).safeGetWriter()
+	tick := time.NewTicker(daemonPollInterval)
+	defer tick.Stop()
+	deadline := time.After(daemonStartupWait)
+	for {
+		select {
+		case e := <-exited:
+			return fmt.Errorf("the daemon exited at startup (%v), see %s", e, logFile)
+		case <-tick.C:
+			if pid, alive := readPid(pidFile); alive && pid == c.Process.Pid {
+				_, _ = fmt.Fprintf(wr, "%s started, pid %d, log: %s\n", w.root.AppName, pid, logFile)
+				return
+			}
+		case <-deadline:
+			_, _ = fmt.Fprintf(wr, "%s is starting, pid %d, log: %s\n", w.root.AppName, c.Process.Pid, logFile)
+			return
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (w *workerS) serverStop(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	return w.stopDaemon(ctx, cmd.Store().MustBool("force"))
+}
+
+// stopDaemon sends SIGTERM to the recorded pid and waits for its
+// exiting, the daemon is killed by SIGKILL if force is set and it
+// doesn't stop in time.
+func (w *workerS) stopDaemon(ctx context.Context, force bool) (err error) {
+	pidFile := w.pidFile()
+	wr := (&helpPrinter{w: w}).safeGetWriter()
+	pid, alive := readPid(pidFile)
+	dryRun := w.dryRun()
+	if !alive {
+		if pid > 0 && !dryRun {
+			removePid(pidFile, pid) // stale
+		}
+		_, _ = fmt.Fprintf(wr, "%s is not running\n", w.root.AppName)
+		return
+	}
+	if dryRun {
+		return w.printDryRun(fmt.Sprintf("kill -TERM %d", pid))
+	}
+
+	if err = terminateProcess(pid); err != nil {
+		return
+	}
+	if !waitExited(ctx, pid, w.stopTimeout()) {
+		if !force {
+			return fmt.Errorf("the daemon (pid %d) doesn't stop in %v, try '--force'", pid, w.stopTimeout())
+		}
+		if err = killProcess(pid); err != nil {
+			return
+		}
+		if !waitExited(ctx, pid, time.Second) {
+			return fmt.Errorf("cannot kill the daemon (pid %d)", pid)
+		}
+	}
+	removePid(pidFile, pid)
+	_, _ = fmt.Fprintf(wr, "%s stopped, pid %d\n", w.root.AppName, pid)
+	return
+}
+
+func waitExited(ctx context.Context, pid int, timeout time.Duration) bool {
+	tick := time.NewTicker(daemonPollInterval)
+	defer tick.Stop()
+	deadline := time.After(timeout)
+	for processAlive(pid) {
+		select {
+		case <-tick.C:
+		case <-deadline:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+func (w *workerS) serverRestart(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	if err = w.stopDaemon(ctx, false); err != nil {
+		return
+	}
+	return w.startBackground(ctx, cmd)
+}
+
+func (w *workerS) serverStatus(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	pid, alive := readPid(w.pidFile())
+	if !alive {
+		return cli.ErrNotRunning
+	}
+	_, _ = fmt.Fprintf((&helpPrinter{w: w}).safeGetWriter(), "%s is running, pid %d\n", w.root.AppName, pid)
+	return
+}
+
+func (w *workerS) serverReload(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	pid, alive := readPid(w.pidFile())
+	if !alive {
+		return cli.ErrNotRunning
+	}
+	if w.dryRun() {
+		return w.printDryRun(fmt.Sprintf("kill -HUP %d", pid))
+	}
+	if err = reloadProcess(pid); err == nil {
+		_, _ = fmt.Fprintf((&helpPrinter{w: w}).safeGetWriter(), "%s is reloading, pid %d\n", w.root.AppName, pid)
+	}
+	return
+}
+
+// systemdUnit returns the unit file which runs the daemon in
+// foreground.
+func (w *workerS) systemdUnit(exe string) string {
+	o := w.Config.Server
+	desc := o.Description
+	if desc == "" {
+		desc = w.root.Cmd.Desc()
+	}
+	if desc == "" {
+		desc = w.root.AppName
+	}
+	after := o.After
+	if len(after) == 0 {
+		after = []string{"network.target"}
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "[Unit]\nDescription=%s\nAfter=%s\n\n", desc, strings.Join(after, " "))
+	_, _ = fmt.Fprintf(&sb, "[Service]\nType=simple\nExecStart=%s server start --foreground\n",
+		systemdQuote(strings.ReplaceAll(exe, "$", "$$"))) // no variable expanding in the path
+	_, _ = sb.WriteString("ExecReload=/bin/kill -HUP $MAINPID\nRestart=on-failure\n")
+	if o.User != "" {
+		_, _ = fmt.Fprintf(&sb, "User=%s\n", o.User)
+	}
+	if o.Group != "" {
+		_, _ = fmt.Fprintf(&sb, "Group=%s\n", o.Group)
+	}
+	for _, env := range o.Environment {
+		_, _ = fmt.Fprintf(&sb, "Environment=%s\n", systemdQuote(env))
+	}
+	_, _ = sb.WriteString("\n[Install]\nWantedBy=multi-user.target\n")
+	return sb.String()
+}
+
+// systemdQuote quotes s as one word of the unit file, the
+// backslashes and double quotes are escaped, and the specifier
+// prefix '%' is doubled.
+func systemdQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s) + `"`
+}
+
+func (w *workerS) genSystemd(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if p, e := filepath.EvalSymlinks(exe); e == nil {
+		exe = p
+	}
+	unit := w.systemdUnit(exe)
+	if out := cmd.Store().MustString("output"); out != "" {
+		if err = os.WriteFile(out, []byte(unit), 0o644); err == nil {
+			logz.InfoContext(ctx, "[cmdr] systemd unit generated", "file", out)
+		}
+		return
+	}
+	_, err = (&helpPrinter{w: w}).safeGetWriter().WriteString(unit)
+	return
+}
+
+var errNotSupported = errors.New("not supported on this platform")
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package worker
+
+import (
+	osexec "os/exec"
+)
+
+// processAlive always returns false since the pidfile based
+// daemon is not supported.
+func processAlive(pid int) bool { return false }
+
+func terminateProcess(pid int) error { return errNotSupported }
+
+func killProcess(pid int) error { return errNotSupported }
+
+func reloadProcess(pid int) error { return errNotSupported }
+
+func detach(c *osexec.Cmd) {}
@@ -0,0 +1,260 @@
+//go:build unix
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_ServerPidFile(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "run", "app.pid")
+	if pid, alive := readPid(pidFile); pid != 0 || alive {
+		t.Fatalf("expecting no pid, got %d, %v", pid, alive)
+	}
+	if err := writePid(pidFile, os.Getpid()); err != nil {
+		t.Fatal(err)
+	}
+	if pid, alive := readPid(pidFile); pid != os.Getpid() || !alive {
+		t.Fatalf("expecting pid %d alive, got %d, %v", os.Getpid(), pid, alive)
+	}
+	removePid(pidFile, os.Getpid()+1)
+	if _, err := os.Stat(pidFile); err != nil {
+		t.Fatal("the pidfile of another pid should be kept")
+	}
+	removePid(pidFile, os.Getpid())
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Fatal("expecting the pidfile removed")
+	}
+}
+
+// serverApp returns the demo app with the builtin command group
+// 'server', the one of the demo app is removed.
+func serverApp(t *testing.T, o *cli.ServerOptions) *testApp {
+	ta := newTestApp(t, context.Background(), func(s *cli.Config) {
+		s.Server = o
+	})
+	ta.removeCommand("server")
+	return ta
+}
+
+func TestWorkerS_ServerForeground(t *testing.T) {
+	ctx := context.Background()
+	pidFile := filepath.Join(t.TempDir(), "app.pid")
+
+	var served bool
+	ww := serverApp(t, &cli.ServerOptions{
+		PidFile: pidFile,
+		Serve: func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			pid, alive := readPid(pidFile)
+			served = pid == os.Getpid() && alive
+			return
+		},
+	}).ww
+	if err := ww.serveForeground(ctx, ww.root.Cmd, nil); err != nil || !served {
+		t.Fatalf("expecting Serve invoked with the pidfile, served=%v, err=%v", served, err)
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Fatal("expecting the pidfile removed after serving")
+	}
+
+	// another instance is running
+	sleep := spawnSleep(t)
+	if err := writePid(pidFile, sleep.Process.Pid); err != nil {
+		t.Fatal(err)
+	}
+	if err := ww.serveForeground(ctx, ww.root.Cmd, nil); !errors.Is(err, cli.ErrAlreadyRunning) {
+		t.Fatalf("expecting ErrAlreadyRunning, got %v", err)
+	}
+}
+
+func spawnSleep(t *testing.T) *osexec.Cmd {
+	c := osexec.Command("sleep", "30")
+	if err := c.Start(); err != nil {
+		t.Skipf("cannot spawn sleep: %v", err)
+	}
+	done := make(chan struct{})
+	go func() { _ = c.Wait(); close(done) }()
+	t.Cleanup(func() {
+		_ = c.Process.Kill()
+		<-done
+	})
+	// the zombie is reaped by Wait, so processAlive reports false
+	// once the child exited.
+	return c
+}
+
+func TestWorkerS_ServerControl(t *testing.T) {
+	ctx := context.Background()
+	pidFile := filepath.Join(t.TempDir(), "app.pid")
+	ta := serverApp(t, &cli.ServerOptions{PidFile: pidFile, StopTimeout: 3 * time.Second})
+	ww, sb, cmd := ta.ww, ta.sb, ta.ww.root.Cmd
+
+	if err := ww.serverStatus(ctx, cmd, nil); !errors.Is(err, cli.ErrNotRunning) || cli.ExitCodeOf(err) != cli.ExitNotRunning {
+		t.Fatalf("expecting ErrNotRunning with exit code 3, got %v", err)
+	}
+	if err := ww.serverReload(ctx, cmd, nil); !errors.Is(err, cli.ErrNotRunning) {
+		t.Fatalf("expecting ErrNotRunning, got %v", err)
+	}
+
+	sleep := spawnSleep(t)
+	pid := sleep.Process.Pid
+	if err := writePid(pidFile, pid); err != nil {
+		t.Fatal(err)
+	}
+	if err := ww.serverStatus(ctx, cmd, nil); err != nil || !strings.Contains(sb.String(), "is running, pid") {
+		t.Fatalf("expecting running, got %v, %q", err, sb.String())
+	}
+	if err := ww.stopDaemon(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	if processAlive(pid) || !strings.Contains(sb.String(), "stopped, pid") {
+		t.Fatalf("expecting the daemon stopped, got %q", sb.String())
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Fatal("expecting the pidfile removed after stopping")
+	}
+	if err := ww.stopDaemon(ctx, false); err != nil || !strings.Contains(sb.String(), "is not running") {
+		t.Fatalf("stopping a stopped daemon should be ok, got %v", err)
+	}
+}
+
+func TestWorkerS_ServerDaemonArgs(t *testing.T) {
+	ctx := context.Background()
+	ta := serverApp(t, &cli.ServerOptions{PidFile: filepath.Join(t.TempDir(), "app.pid")})
+	err := ta.run(ctx, "--verbose", "--profile", "restart", "--timeout", "1h", "--dry-run", "--yes", "daemon", "status")
+	if !errors.Is(err, cli.ErrNotRunning) {
+		t.Fatalf("expecting ErrNotRunning, got %v", err)
+	}
+	cc := ta.ww.root.Cmd.FindSubCommand(ctx, "server", false).FindSubCommand(ctx, "restart", false)
+	if got := strings.Join(ta.ww.daemonArgs(cc), " "); got != "--profile=restart --verbose server start --foreground" {
+		t.Fatalf("unexpected daemon args: %q", got)
+	}
+}
+
+func TestWorkerS_ServerCommands(t *testing.T) {
+	ctx := context.Background()
+	pidFile := filepath.Join(t.TempDir(), "app.pid")
+
+	var served bool
+	run := func(args ...string) (out string, err error) {
+		ta := serverApp(t, &cli.ServerOptions{
+			PidFile:     pidFile,
+			StopTimeout: 3 * time.Second,
+			Serve: func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+				served = true
+				return
+			},
+		})
+		err = ta.run(ctx, args...)
+		return ta.sb.String(), err
+	}
+
+	if _, err := run("server", "status"); !errors.Is(err, cli.ErrNotRunning) || cli.ExitCodeOf(err) != cli.ExitNotRunning {
+		t.Fatalf("expecting ErrNotRunning with exit code 3, got %v", err)
+	}
+	if _, err := run("server", "start", "--foreground"); err != nil || !served {
+		t.Fatalf("expecting Serve invoked by 'server start --foreground', served=%v, err=%v", served, err)
+	}
+
+	sleep := spawnSleep(t)
+	pid := sleep.Process.Pid
+	if err := writePid(pidFile, pid); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := run("server", "status"); err != nil || !strings.Contains(out, "is running, pid") {
+		t.Fatalf("expecting running, got %v, %q", err, out)
+	}
+
+	// '--dry-run' prints what would be done
+	kill := fmt.Sprintf("[dry-run] kill -TERM %d\n", pid)
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"server", "stop", "--dry-run"}, kill},
+		{[]string{"server", "reload", "--dry-run"}, fmt.Sprintf("[dry-run] kill -HUP %d\n", pid)},
+		{[]string{"server", "restart", "--dry-run"}, kill + "[dry-run] start "},
+		{[]string{"server", "start", "--foreground", "--dry-run"}, "[dry-run] serve in foreground, pidfile: " + pidFile + "\n"},
+	} {
+		if out, err := run(c.args...); err != nil || !strings.HasPrefix(out, c.want) || !processAlive(pid) {
+			t.Fatalf("%v: expecting %q printed only, got %q, %v", c.args, c.want, out, err)
+		}
+	}
+
+	if out, err := run("server", "stop"); err != nil || !strings.Contains(out, "stopped, pid") || processAlive(pid) {
+		t.Fatalf("expecting the daemon stopped, got %v, %q", err, out)
+	}
+	if out, err := run("daemon", "stop"); err != nil || !strings.Contains(out, "is not running") {
+		t.Fatalf("stopping a stopped daemon should be ok, got %v, %q", err, out)
+	}
+}
+
+func TestWorkerS_GenSystemd(t *testing.T) {
+	ctx := context.Background()
+	out := filepath.Join(t.TempDir(), "app.service")
+	o := &cli.ServerOptions{
+		Description: "demo daemon",
+		User:        "nobody",
+		Environment: []string{"GOMAXPROCS=2", `MSG=say "100%"`},
+	}
+
+	// the demo app has its own 'server', the builtin 'server' and
+	// 'generate systemd' are skipped.
+	ta := newTestApp(t, ctx, func(s *cli.Config) { s.Server = o })
+	if err := ta.run(ctx, "--version"); err != nil {
+		t.Fatal(err)
+	}
+	root := ta.ww.root.Cmd
+	if cc := root.FindSubCommand(ctx, "server", false); cc == nil || cc.Desc() == "Manage the daemon of this app" {
+		t.Fatal("expecting the command 'server' of app kept")
+	}
+	if cc := root.FindSubCommand(ctx, "generate", false); cc == nil || cc.FindSubCommand(ctx, "systemd", false) != nil {
+		t.Fatal("expecting 'generate systemd' skipped with the builtin 'server'")
+	}
+
+	ta = serverApp(t, o)
+	if err := ta.run(ctx, "generate", "systemd", "-o", out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit := string(data)
+	for _, want := range []string{
+		"Description=demo daemon\n",
+		"After=network.target\n",
+		"\" server start --foreground\n",
+		"ExecReload=/bin/kill -HUP $MAINPID\n",
+		"User=nobody\n",
+		"Environment=\"GOMAXPROCS=2\"\n",
+		`Environment="MSG=say \"100%%\""` + "\n",
+		"WantedBy=multi-user.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Fatalf("expecting %q in the unit file:\n%s", want, unit)
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	for _, c := range []struct{ in, want string }{
+		{"/usr/bin/app", `"/usr/bin/app"`},
+		{"/opt/my app/bin/app", `"/opt/my app/bin/app"`},
+		{`a\b "c" 50%`, `"a\\b \"c\" 50%%"`},
+	} {
+		if got := systemdQuote(c.in); got != c.want {
+			t.Fatalf("systemdQuote(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package worker
+
+import (
+	"errors"
+	osexec "os/exec"
+	"syscall"
+)
+
+// processAlive reports whether pid exists, a process owned by
+// another user is still alive.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// terminateProcess asks pid to stop by SIGTERM.
+func terminateProcess(pid int) error { return syscall.Kill(pid, syscall.SIGTERM) }
+
+// killProcess kills pid by SIGKILL.
+func killProcess(pid int) error { return syscall.Kill(pid, syscall.SIGKILL) }
+
+// reloadProcess asks pid to reload by SIGHUP.
+func reloadProcess(pid int) error { return syscall.Kill(pid, syscall.SIGHUP) }
+
+// detach starts the daemon in a new session, so it has no
+// controlling terminal and survives the exiting of the caller.
+func detach(c *osexec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
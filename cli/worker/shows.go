@@ -157,6 +157,9 @@ func (w *workerS) showSBOM(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, a
 }
 
 func (w *workerS) showHelpScreen(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, args ...any) (err error) {
+	if isTreeExportFormat(w.format) {
+		return w.showTreeExport(ctx, pc, lastCmd, w.format)
+	}
 	(&helpPrinter{w: w}).Print(ctx, pc, lastCmd, args...)
 	return
 }
@@ -213,10 +216,24 @@ func (w *workerS) showHelpScreenAsMan(ctx context.Context, pc *parseCtx, lastCmd
 
 // helpSystemAction is the reaction for 'help' command at root level.
 func (w *workerS) helpSystemAction(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+	if isCompleteRequest(cmd) {
+		return w.completeArgs(ctx, cmd, args)
+	}
+
+	if w.searchTerms != "" {
+		// `help --search TERMS`, the rest args are the terms too.
+		_, err = w.DoBuiltinAction(ctx, cli.ActionShowSearch, cmd, w.searchTerms, args)
+		return
+	}
+
 	if len(args) > 0 {
 		hp, handled := &helpPrinter{w: w}, cmd
 		// trying to recognize the given commands and print help screen of it.
 		if handled, err = hs.New(w, cmd, args).FindCmd(ctx, cmd, args); handled == nil {
+			if t := w.helpTopic(strings.Join(args, " ")); t != nil {
+				hp.printHelpTopic(ctx, hp.safeGetWriter(), w.parsingCtx, t)
+				err = nil
+			}
 			return
 		}
 		hp.Print(ctx, w.parsingCtx, handled)
@@ -229,10 +246,37 @@ func (w *workerS) helpSystemAction(ctx context.Context, cmd cli.Cmd, args []stri
 }
 
 func (w *workerS) showTree(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, args ...any) (err error) {
+	if isTreeExportFormat(w.format) {
+		return w.showTreeExport(ctx, pc, lastCmd, w.format)
+	}
+	if isTreeGraphFormat(w.format) {
+		return w.showTreeGraph(ctx, pc, lastCmd, w.format)
+	}
 	(&helpPrinter{w: w, debugMatches: true, treeMode: true}).Print(ctx, pc, lastCmd, args...)
 	return
 }
 
+// showEnvVars shows the environment for `~~env`, or the
+// envvars reference for `~~env --all`.
+func (w *workerS) showEnvVars(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, args ...any) (err error) {
+	if w.actionsMatched&cli.ActionShowDebug != 0 {
+		// `~~debug --env`: the env will be printed in debug screen
+		return w.showDebugScreen(ctx, pc, lastCmd, args...)
+	}
+
+	hp := &helpPrinter{w: w}
+	wr := hp.safeGetWriter()
+	if w.envAll {
+		err = printEnvVarsReference(wr, w.envVarsReference(ctx), w.format)
+		return
+	}
+
+	var sb strings.Builder
+	hp.printEnvironments(&sb)
+	_, err = wr.WriteString(sb.String())
+	return
+}
+
 func (w *workerS) showDebugScreen(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, args ...any) (err error) {
 	(&helpPrinter{w: w, debugScreenMode: true, debugMatches: true}).Print(ctx, pc, lastCmd, args...)
 	return
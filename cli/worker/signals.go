@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// osExit can be replaced for testing.
+var osExit = os.Exit
+
+// defaultGracePeriod is used if [cli.Config.GracePeriod] is zero.
+const defaultGracePeriod = 10 * time.Second
+
+func (w *workerS) gracePeriod() time.Duration {
+	if w.Config != nil && w.Config.GracePeriod > 0 {
+		return w.Config.GracePeriod
+	}
+	return defaultGracePeriod
+}
+
+// handleSignals listens SIGINT, SIGTERM and SIGHUP while Run is
+// running, if [cli.Config.HandleSignals] is set.
+//
+// The first SIGINT or SIGTERM cancels the Run context, and the
+// action has [cli.Config.GracePeriod] to return. A second one,
+// or the expiration of grace period, forces exiting with the
+// conventional code 130 or 143, the peripherals are closed
+// before exiting if the grace period expired. SIGHUP is routed
+// to [cli.Config.OnReload], and terminates the app as usual if
+// there is no handler.
+//
+// The returned stop function must be called when Run is ending.
+func (w *workerS) handleSignals(ctx context.Context, cancel context.CancelFunc) (stop func()) {
+	if w.Config == nil || !w.Config.HandleSignals || cancel == nil {
+		return func() {}
+	}
+	return w.listenSignals(ctx, cancel)
+}
+
+// listenSignals listens SIGINT, SIGTERM and SIGHUP, see
+// handleSignals.
+func (w *workerS) listenSignals(ctx context.Context, cancel context.CancelFunc) (stop func()) {
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, w.signalsToWatch()...)
+	done := make(chan struct{})
+	go w.signalLoop(ctx, cancel, ch, done)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// signalsToWatch returns the signals to listen. SIGHUP is only
+// listened if there is an OnReload handler, otherwise it keeps
+// the default action, which terminates the app.
+func (w *workerS) signalsToWatch() []os.Signal {
+	if sigReload != nil && w.Config.OnReload != nil {
+		return append(slices.Clip(watchedSignals), sigReload)
+	}
+	return watchedSignals
+}
+
+func (w *workerS) signalLoop(ctx context.Context, cancel context.CancelFunc, ch <-chan os.Signal, done <-chan struct{}) {
+	var first os.Signal
+	var grace <-chan time.Time
+	for {
+		select {
+		case <-done:
+			return
+		case <-grace:
+			logz.WarnContext(ctx, "[cmdr] grace period expired, force exiting", "signal", first, "grace", w.gracePeriod())
+			w.closePeripherals(ctx) // the deferred closing in Run will not be reached
+			osExit(exitCodeOf(first))
+			return
+		case sig := <-ch:
+			if sigReload != nil && sig == sigReload {
+				w.reload(ctx, sig)
+				continue
+			}
+			if first != nil {
+				logz.WarnContext(ctx, "[cmdr] signal received again, force exiting", "signal", sig)
+				osExit(exitCodeOf(sig))
+				return
+			}
+			first = sig
+			w.signalCode.Store(int32(exitCodeOf(sig)))
+			logz.InfoContext(ctx, "[cmdr] signal received, shutting down", "signal", sig, "grace", w.gracePeriod())
+			cancel()
+			grace = time.After(w.gracePeriod())
+		}
+	}
+}
+
+// reload invokes [cli.Config.OnReload] for SIGHUP.
+func (w *workerS) reload(ctx context.Context, sig os.Signal) {
+	if w.Config.OnReload == nil {
+		logz.VerboseContext(ctx, "[cmdr] signal ignored, no OnReload handler", "signal", sig)
+		return
+	}
+	if err := w.Config.OnReload(ctx, sig); err != nil {
+		logz.ErrorContext(ctx, "[cmdr] reload failed", "signal", sig, "err", err)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !plan9 && !js
+
+package worker
+
+import (
+	"os"
+	"syscall"
+)
+
+// sigReload is routed to [cli.Config.OnReload].
+var sigReload os.Signal = syscall.SIGHUP
+
+// watchedSignals are listened by listenSignals, sigReload is
+// listened too if there is an OnReload handler.
+var watchedSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// exitCodeOf returns the conventional exit code of a signal,
+// 128+n, such as 130 for SIGINT and 143 for SIGTERM.
+func exitCodeOf(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
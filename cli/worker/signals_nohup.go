@@ -0,0 +1,24 @@
+//go:build plan9 || js
+
+package worker
+
+import (
+	"os"
+)
+
+// sigReload is nil since there is no SIGHUP on this platform, so
+// [cli.Config.OnReload] is never invoked.
+var sigReload os.Signal
+
+// watchedSignals are listened by listenSignals, sigReload is
+// listened too if there is an OnReload handler.
+var watchedSignals = []os.Signal{os.Interrupt}
+
+// exitCodeOf returns the conventional exit code 130 for an
+// interrupt, or 1.
+func exitCodeOf(sig os.Signal) int {
+	if sig == os.Interrupt {
+		return 130
+	}
+	return 1
+}
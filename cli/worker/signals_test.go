@@ -0,0 +1,121 @@
+//go:build !plan9 && !js
+
+package worker
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_signalLoop(t *testing.T) {
+	exits := make(chan int, 1)
+	osExit = func(code int) { exits <- code }
+	defer func() { osExit = os.Exit }()
+
+	var closed []string
+	m := cli.NewPeripheralManager()
+	m.Add("db", &orderedCloser{name: "db", closed: &closed})
+	reloaded := make(chan os.Signal, 1)
+	w := &workerS{Config: &cli.Config{
+		HandleSignals: true,
+		GracePeriod:   50 * time.Millisecond,
+		Peripherals:   m,
+		OnReload: func(ctx context.Context, sig os.Signal) (err error) {
+			reloaded <- sig
+			return
+		},
+	}}
+
+	run := func(signals ...os.Signal) (code int, canceled bool) {
+		w.signalCode.Store(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch, done := make(chan os.Signal, len(signals)), make(chan struct{})
+		defer close(done)
+		go w.signalLoop(ctx, cancel, ch, done)
+		for _, sig := range signals {
+			ch <- sig
+		}
+		select {
+		case code = <-exits:
+		case <-time.After(time.Second):
+			t.Fatal("expecting force exiting, but timed out")
+		}
+		return code, ctx.Err() != nil
+	}
+
+	// SIGHUP reloads, SIGINT cancels, and the grace period expires
+	if code, canceled := run(syscall.SIGHUP, syscall.SIGINT); code != 130 || !canceled {
+		t.Fatalf("expecting exit code 130 and context canceled, got %d, %v", code, canceled)
+	}
+	if sig := <-reloaded; sig != syscall.SIGHUP {
+		t.Fatalf("expecting OnReload invoked with SIGHUP, got %v", sig)
+	}
+	if c := w.signalCode.Load(); c != 130 {
+		t.Fatalf("expecting signalCode 130, got %d", c)
+	}
+	if want := []string{"db"}; !reflect.DeepEqual(closed, want) {
+		t.Fatalf("expecting the peripherals closed before force exiting, got %v", closed)
+	}
+
+	// the second signal forces exiting at once
+	w.Config.GracePeriod = time.Hour
+	if code, _ := run(syscall.SIGTERM, syscall.SIGTERM); code != 143 {
+		t.Fatalf("expecting exit code 143, got %d", code)
+	}
+}
+
+func TestWorkerS_signalsToWatch(t *testing.T) {
+	w := &workerS{Config: &cli.Config{HandleSignals: true}}
+	if sigs := w.signalsToWatch(); slices.Contains(sigs, os.Signal(syscall.SIGHUP)) {
+		t.Fatalf("SIGHUP should keep its default action without OnReload, got %v", sigs)
+	}
+	w.Config.OnReload = func(ctx context.Context, sig os.Signal) error { return nil }
+	if sigs := w.signalsToWatch(); !slices.Contains(sigs, os.Signal(syscall.SIGHUP)) || len(watchedSignals) != 2 {
+		t.Fatalf("expecting SIGHUP listened for OnReload, got %v", sigs)
+	}
+}
+
+type orderedCloser struct {
+	name   string
+	closed *[]string
+	delay  time.Duration
+}
+
+func (c *orderedCloser) Close() {
+	time.Sleep(c.delay)
+	if c.closed != nil {
+		*c.closed = append(*c.closed, c.name)
+	}
+}
+
+func TestWorkerS_closePeripherals(t *testing.T) {
+	var closed []string
+	m := cli.NewPeripheralManager()
+	m.Add("db", &orderedCloser{name: "db", closed: &closed})
+	m.Add("slow", &orderedCloser{name: "slow", delay: time.Second}, "db") // abandoned
+	m.Add("cache", &orderedCloser{name: "cache", closed: &closed}, "slow")
+	w := &workerS{Config: &cli.Config{
+		CloseTimeout: 20 * time.Millisecond,
+		Peripherals:  m,
+	}}
+
+	start := time.Now()
+	w.closePeripherals(context.Background())
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("expecting the slow closer abandoned, but closing took %v", d)
+	}
+	if want := []string{"cache", "db"}; !reflect.DeepEqual(closed, want) {
+		t.Fatalf("expecting closed in reverse order %v, got %v", want, closed)
+	}
+	if w.Config.Peripherals.Len() != 0 {
+		t.Fatal("expecting the peripherals cleared after closed")
+	}
+}
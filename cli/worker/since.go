@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// unreleased tests if an item with Since(since) is newer than
+// the version simulated by `--version-sim`, so that it should
+// be hidden.
+func (w *workerS) unreleased(since string) bool {
+	if w == nil || w.versionSimulate == "" || since == "" {
+		return false
+	}
+	return compareVersions(since, w.versionSimulate) > 0
+}
+
+// unreleasedBR tests Since of cc and its owners backwards
+// recursively.
+func (w *workerS) unreleasedBR(cc cli.Cmd) bool {
+	if w == nil || w.versionSimulate == "" {
+		return false
+	}
+	for c := cc; c != nil; c = c.OwnerCmd() {
+		if w.unreleased(cli.SinceOf(c)) {
+			return true
+		}
+		if c.OwnerIsNil() {
+			break
+		}
+	}
+	return false
+}
+
+// unreleasedFlag tests Since of ff and its owners.
+func (w *workerS) unreleasedFlag(ff *cli.Flag) bool {
+	if w.unreleased(ff.Since()) {
+		return true
+	}
+	if ff.OwnerIsNotNil() {
+		return w.unreleasedBR(ff.OwnerCmd())
+	}
+	return false
+}
+
+// compareVersions compares two versions like 'v1.4', '1.4.2'
+// and 'v2.0.0-rc1', returns -1, 0 or 1.
+//
+// The missing parts are treated as zero, and a pre-release is
+// older than its release.
+func compareVersions(a, b string) int {
+	split := func(v string) (nums []int, pre string) {
+		v = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(v)), "v")
+		if i := strings.IndexByte(v, '+'); i >= 0 {
+			v = v[:i] // build metadata
+		}
+		if i := strings.IndexByte(v, '-'); i >= 0 {
+			v, pre = v[:i], v[i+1:]
+		}
+		for _, p := range strings.Split(v, ".") {
+			n, _ := strconv.Atoi(p)
+			nums = append(nums, n)
+		}
+		return
+	}
+
+	na, pa := split(a)
+	nb, pb := split(b)
+	for i := 0; i < max(len(na), len(nb)); i++ {
+		var x, y int
+		if i < len(na) {
+			x = na[i]
+		}
+		if i < len(nb) {
+			y = nb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case pa == pb:
+		return 0
+	case pa == "":
+		return 1
+	case pb == "":
+		return -1
+	case pa < pb:
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/pkg/logz"
+)
+
+// theme resolves the colors of help and debug screens.
+//
+// The theme is chosen by (in priority order):
+//
+//   - config entry `app.theme.name`, a builtin theme name
+//   - [cli.Config.Theme], see also cmdr.WithTheme()
+//   - the package-level Current*Color variables
+//
+// And then the roles are overridden by the config entries
+// `app.theme.<role>`, such as `app.theme.desc: blue`.
+func (w *workerS) theme() (th *cli.Theme) {
+	if w.Config == nil {
+		return currentTheme()
+	}
+	if w.Config.Theme != nil {
+		th = w.Config.Theme.Clone()
+	} else {
+		th = currentTheme()
+	}
+
+	conf := w.Store()
+	if conf == nil {
+		return
+	}
+	if name := conf.MustString("theme.name"); name != "" {
+		if t := cli.ThemeByName(name); t != nil {
+			th = t.Clone()
+		} else {
+			logz.Warn("[cmdr] unknown theme", "theme", name)
+		}
+	}
+	for _, role := range cli.ThemeRoles {
+		if v := conf.MustString("theme." + role); v != "" {
+			if clr, ok := cli.ParseColor(v); ok {
+				*th.Role(role) = clr
+			} else {
+				logz.Warn("[cmdr] unknown color in theme", "role", role, "color", v)
+			}
+		}
+	}
+	return
+}
+
+// currentTheme makes a theme from the package-level colors.
+func currentTheme() *cli.Theme {
+	return &cli.Theme{
+		Name:         "default",
+		Title:        CurrentTitleColor,
+		FlagTitle:    CurrentFlagTitleColor,
+		Hidden:       CurrentHiddenColor,
+		Deprecated:   CurrentDeprecatedColor,
+		EnvVars:      CurrentEnvVarsColor,
+		Desc:         CurrentDescColor,
+		DefaultValue: CurrentDefaultValueColor,
+		GroupTitle:   CurrentGroupTitleColor,
+		GroupTitleBg: CurrentGroupTitleBgColor,
+	}
+}
+
+// colors returns the resolved theme of this printer.
+func (s *helpPrinter) colors() *cli.Theme {
+	if s.th == nil {
+		if s.w != nil {
+			s.th = s.w.theme()
+		} else {
+			s.th = currentTheme()
+		}
+	}
+	return s.th
+}
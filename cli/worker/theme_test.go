@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/hedzr/is/term/color"
+	"github.com/hedzr/store"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_Theme(t *testing.T) {
+	w := &workerS{Config: &cli.Config{Store: store.New()}}
+	if th := w.theme(); th.Desc != CurrentDescColor || th.FlagTitle != CurrentFlagTitleColor {
+		t.Fatalf("expecting the package-level colors by default, but got %+v", th)
+	}
+
+	w.Config.Theme = cli.ThemeLight
+	if th := w.theme(); th.Desc != cli.ThemeLight.Desc {
+		t.Fatalf("expecting light theme, but got %+v", th)
+	}
+
+	conf := w.Store()
+	conf.Set("theme.name", "high-contrast")
+	conf.Set("theme.desc", "light-blue")
+	conf.Set("theme.group-title-bg", "underline")
+	th := w.theme()
+	if th.Name != "high-contrast" || th.Desc != color.FgLightBlue || th.GroupTitleBg != color.BgUnderline ||
+		th.FlagTitle != cli.ThemeHighContrast.FlagTitle {
+		t.Fatalf("expecting high-contrast theme with overrides, but got %+v", th)
+	}
+	if cli.ThemeHighContrast.Desc == color.FgLightBlue {
+		t.Fatal("the builtin theme should not be modified")
+	}
+
+	if _, ok := cli.ParseColor("no-such-color"); ok {
+		t.Fatal("expecting unknown color")
+	}
+	if clr, ok := cli.ParseColor("Dark_Grey"); !ok || clr != color.FgDarkGray {
+		t.Fatalf("expecting dark gray, but got %v", clr)
+	}
+}
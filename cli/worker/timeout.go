@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"time"
+
+	"github.com/hedzr/is/exec"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/internal/tool"
+)
+
+// timeoutOf returns the deadline of the action of cmd. The
+// builtin flag `--timeout` overrides the one given by
+// [cli.CommandBuilder.Timeout].
+func (w *workerS) timeoutOf(cmd cli.Cmd) time.Duration {
+	if w.timeout > 0 {
+		return w.timeout
+	}
+	if cc, ok := cmd.(*cli.CmdS); ok {
+		return cc.Timeout()
+	}
+	return 0
+}
+
+// stdinIsTerminal can be replaced for testing.
+var stdinIsTerminal = tool.StdinIsTerminal
+
+// invokeExternal runs the alias command of cmd (InvokeShell or
+// InvokeProc) within the deadline d. The process is killed
+// once it timed out.
+func (w *workerS) invokeExternal(ctx context.Context, cmd cli.Cmd, d time.Duration, name string, args ...string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	c := osexec.CommandContext(ctx, name, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	killGroupOnCancel(c, stdinIsTerminal())
+	err = c.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = &cli.TimeoutError{Cmd: cmd, Timeout: d}
+	}
+	return
+}
+
+// invokeAlias runs an alias command of cmd: InvokeShell by the
+// shell of cmd (`SHELL -c LINE`), or a command line such as
+// InvokeProc. The alias is killed once it exceeded the deadline
+// d, and zero means no limit.
+//
+// A command line is split into the program and its args, and
+// an InvokeProc is called by exec.Call unless it runs a shell
+// itself (`sh -c ...`). The timed ones run the same program and
+// args by invokeExternal.
+func (w *workerS) invokeAlias(ctx context.Context, cmd cli.Cmd, d time.Duration, line, shell string, proc bool) (err error) {
+	argv := []string{shell, "-c", line}
+	if shell == "" {
+		if argv = exec.SplitCommandString(strings.TrimSpace(line)); len(argv) == 0 {
+			return
+		}
+	}
+
+	switch {
+	case d > 0:
+		err = w.invokeExternal(ctx, cmd, d, argv[0], argv[1:]...)
+	case shell != "":
+		err = exec.New().WithCommand(shell, "-c", line).RunAndCheckError()
+	case proc && !strings.Contains(line, "sh -c "):
+		err = exec.Call(line, nil)
+	default:
+		err = exec.New().WithCommandString(line).RunAndCheckError()
+	}
+	return
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package worker
+
+import osexec "os/exec"
+
+// killGroupOnCancel keeps the default behavior, which kills the
+// process only.
+func killGroupOnCancel(c *osexec.Cmd, interactive bool) {}
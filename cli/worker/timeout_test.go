@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+func TestWorkerS_Timeout(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(timeout time.Duration, args ...string) (ww *workerS, err error) {
+		ta := newTestApp(t, ctx)
+		ta.consul.SetTimeout(timeout)
+		ta.consul.SetAction(func(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			return
+		})
+		return ta.ww, ta.run(ctx, args...)
+	}
+
+	ww, err := run(20*time.Millisecond, "consul")
+	if !errors.Is(err, cli.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expecting a TimeoutError, got %v", err)
+	}
+	if code := ww.SuggestRetCode(); code != cli.ExitCodeTimeout {
+		t.Fatalf("expecting exit code %d, got %d", cli.ExitCodeTimeout, code)
+	}
+
+	// --timeout overrides the one of command
+	if _, err = run(time.Hour, "--timeout", "20ms", "consul"); !errors.Is(err, cli.ErrTimeout) {
+		t.Fatalf("expecting a TimeoutError by --timeout, got %v", err)
+	}
+}
+
+func TestWorkerS_TimeoutInvokeShell(t *testing.T) {
+	ctx := context.Background()
+
+	stdinIsTerminalDefault := stdinIsTerminal
+	defer func() { stdinIsTerminal = stdinIsTerminalDefault }()
+
+	for _, interactive := range []bool{false, true} {
+		stdinIsTerminal = func() bool { return interactive }
+		testTimedAliases(t, ctx)
+	}
+}
+
+func testTimedAliases(t *testing.T, ctx context.Context) { //nolint:revive
+	t.Helper()
+	for _, alias := range []func(cc *cli.CmdS){
+		func(cc *cli.CmdS) { cc.SetShell("/bin/sh"); cc.SetInvokeShell("sleep 5") },
+		func(cc *cli.CmdS) { cc.SetInvokeProc("sleep 5") },
+		func(cc *cli.CmdS) { cc.SetInvokeProc("sh -c 'sleep 5'") },
+	} {
+		ta := newTestApp(t, ctx)
+		ta.consul.SetAction(nil)
+		alias(ta.consul)
+		ta.consul.SetTimeout(50 * time.Millisecond)
+
+		start := time.Now()
+		if err := ta.run(ctx, "consul"); !errors.Is(err, cli.ErrTimeout) {
+			t.Fatalf("expecting a TimeoutError, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Fatalf("expecting the alias killed in time, it took %v", elapsed)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package worker
+
+import (
+	osexec "os/exec"
+	"syscall"
+)
+
+// killGroupOnCancel puts the process into its own process group,
+// and kills the whole group once the context is canceled, so
+// that the children of a shell don't survive the deadline.
+//
+// An interactive process (the stdin is a terminal) stays in the
+// foreground process group to read the terminal and receive
+// Ctrl-C, and only itself is killed.
+func killGroupOnCancel(c *osexec.Cmd, interactive bool) {
+	if interactive {
+		return
+	}
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	c.Cancel = func() error {
+		return syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package worker
+
+import (
+	"context"
+	osexec "os/exec"
+	"testing"
+	"time"
+)
+
+func TestKillGroupOnCancel(t *testing.T) {
+	c := osexec.Command("sleep", "1")
+	killGroupOnCancel(c, true)
+	if c.SysProcAttr != nil || c.Cancel != nil {
+		t.Fatal("an interactive process should stay in the foreground process group")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c = osexec.CommandContext(ctx, "sh", "-c", "sleep 5; exit 0")
+	killGroupOnCancel(c, false)
+	if c.SysProcAttr == nil || !c.SysProcAttr.Setpgid {
+		t.Fatal("a non-interactive process should run in its own process group")
+	}
+	start := time.Now()
+	if err := c.Run(); err == nil || time.Since(start) > 3*time.Second {
+		t.Fatalf("expecting the group killed in time, got %v in %v", err, time.Since(start))
+	}
+}
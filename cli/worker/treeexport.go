@@ -0,0 +1,438 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/is/exec"
+)
+
+// cmdNode is the machine-readable form of a command, used by
+// `--help --format=json|yaml` and `~~tree --json`.
+type cmdNode struct {
+	Name            string     `json:"name"`
+	Path            string     `json:"path,omitempty"` // dotted path, empty for root
+	Shorts          []string   `json:"shorts,omitempty"`
+	Aliases         []string   `json:"aliases,omitempty"`
+	Group           string     `json:"group,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	LongDescription string     `json:"longDescription,omitempty"`
+	Examples        string     `json:"examples,omitempty"`
+	TailPlaceHolder string     `json:"tailPlaceHolder,omitempty"`
+	RedirectTo      string     `json:"redirectTo,omitempty"`
+	Notes           []string   `json:"notes,omitempty"`
+	SeeAlso         []string   `json:"seeAlso,omitempty"`
+	Since           string     `json:"since,omitempty"`
+	Deprecated      string     `json:"deprecated,omitempty"`
+	Hidden          bool       `json:"hidden,omitempty"`
+	VendorHidden    bool       `json:"vendorHidden,omitempty"`
+	Flags           []flagNode `json:"flags,omitempty"`
+	Commands        []*cmdNode `json:"commands,omitempty"`
+}
+
+// flagNode is the machine-readable form of a flag.
+type flagNode struct {
+	Name             string     `json:"name"`
+	Shorts           []string   `json:"shorts,omitempty"`
+	Aliases          []string   `json:"aliases,omitempty"`
+	Group            string     `json:"group,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	LongDescription  string     `json:"longDescription,omitempty"`
+	Examples         string     `json:"examples,omitempty"`
+	Type             string     `json:"type"`
+	Default          any        `json:"default,omitempty"` // a secret one is masked
+	PlaceHolder      string     `json:"placeHolder,omitempty"`
+	EnvVars          []string   `json:"envVars,omitempty"`
+	ValidArgs        []string   `json:"validArgs,omitempty"`
+	Range            *flagRange `json:"range,omitempty"`
+	ToggleGroup      string     `json:"toggleGroup,omitempty"`
+	MutualExclusives []string   `json:"mutualExclusives,omitempty"`
+	Prerequisites    []string   `json:"prerequisites,omitempty"`
+	Required         bool       `json:"required,omitempty"`
+	Secret           bool       `json:"secret,omitempty"`
+	Negatable        bool       `json:"negatable,omitempty"`
+	HeadLike         bool       `json:"headLike,omitempty"`
+	JustOnce         bool       `json:"justOnce,omitempty"`
+	DoubleTildeOnly  bool       `json:"doubleTildeOnly,omitempty"`
+	Notes            []string   `json:"notes,omitempty"`
+	SeeAlso          []string   `json:"seeAlso,omitempty"`
+	Since            string     `json:"since,omitempty"`
+	Deprecated       string     `json:"deprecated,omitempty"`
+	Hidden           bool       `json:"hidden,omitempty"`
+	VendorHidden     bool       `json:"vendorHidden,omitempty"`
+}
+
+type flagRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// exportTree builds the machine-readable command tree from cc,
+// by walking it with WalkEverything.
+//
+// Unlike the help screen, the hidden commands and flags are
+// always included, and marked by Hidden/VendorHidden.
+func (w *workerS) exportTree(ctx context.Context, pc cli.ParsedState, cc cli.Cmd) (tree *cmdNode) {
+	text := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		if pc != nil {
+			s = pc.Translate(exec.StripLeftTabs(s))
+		} else {
+			s = exec.StripLeftTabs(s)
+		}
+		return strings.TrimSpace(s)
+	}
+
+	cx, ok := cc.(*cli.CmdS)
+	if !ok {
+		if rc, ok1 := cc.(*cli.RootCommand); ok1 {
+			cx, ok = rc.Cmd.(*cli.CmdS)
+		}
+	}
+	if !ok {
+		return
+	}
+
+	nodes := make(map[cli.Cmd]*cmdNode)
+	cx.WalkEverything(ctx, func(c, pp cli.Cmd, ff *cli.Flag, cmdIndex, flgIndex, level int) {
+		_, _, _ = cmdIndex, flgIndex, level
+
+		if ff == nil {
+			node := &cmdNode{
+				Name:            c.Name(),
+				Path:            c.GetDottedPath(),
+				Shorts:          c.ShortNames(),
+				Aliases:         c.AliasNames(),
+				Group:           c.GroupHelpTitle(),
+				Description:     text(c.Desc()),
+				LongDescription: text(c.DescLong()),
+				Examples:        text(c.Examples()),
+				TailPlaceHolder: c.TailPlaceHolder(),
+				RedirectTo:      c.RedirectTo(),
+				Notes:           cli.NotesOf(c),
+				SeeAlso:         cli.SeeAlsoOf(c),
+				Since:           cli.SinceOf(c),
+				Deprecated:      c.Deprecated(),
+				Hidden:          c.Hidden(),
+				VendorHidden:    c.VendorHidden(),
+			}
+			if node.LongDescription == node.Description {
+				node.LongDescription = ""
+			}
+			nodes[c] = node
+			if dad := nodes[pp]; dad != nil && pp != nil {
+				dad.Commands = append(dad.Commands, node)
+			} else if tree == nil {
+				tree = node
+			}
+			return
+		}
+
+		node := nodes[c]
+		if node == nil {
+			return
+		}
+		fn := flagNode{
+			Name:             ff.Name(),
+			Shorts:           ff.Shorts(),
+			Aliases:          ff.Aliases,
+			Group:            ff.GroupHelpTitle(),
+			Description:      text(ff.Desc()),
+			LongDescription:  text(ff.DescLong()),
+			Examples:         text(ff.Examples()),
+			Type:             fmt.Sprintf("%T", ff.DefaultValue()),
+			Default:          exportValue(ff.MaskedValue(ff.DefaultValue())),
+			PlaceHolder:      ff.PlaceHolder(),
+			EnvVars:          ff.EnvVars(),
+			ValidArgs:        ff.ValidArgs(),
+			ToggleGroup:      ff.ToggleGroup(),
+			MutualExclusives: ff.MutualExclusives(),
+			Prerequisites:    ff.Prerequisites(),
+			Required:         ff.Required(),
+			Secret:           ff.Secret(),
+			Negatable:        ff.Negatable(),
+			HeadLike:         ff.HeadLike(),
+			JustOnce:         ff.JustOnce(),
+			DoubleTildeOnly:  ff.DoubleTildeOnly(),
+			Notes:            ff.Notes(),
+			SeeAlso:          ff.SeeAlso(),
+			Since:            ff.Since(),
+			Deprecated:       ff.Deprecated(),
+			Hidden:           ff.Hidden(),
+			VendorHidden:     ff.VendorHidden(),
+		}
+		if fn.LongDescription == fn.Description {
+			fn.LongDescription = ""
+		}
+		if mn, mx := ff.Range(); mn != 0 || mx != 0 {
+			fn.Range = &flagRange{Min: mn, Max: mx}
+		}
+		node.Flags = append(node.Flags, fn)
+	})
+	return
+}
+
+// exportValue converts the value which has no suitable JSON form,
+// such as time.Duration, to string.
+func exportValue(val any) any {
+	switch v := val.(type) {
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	if _, err := json.Marshal(val); err != nil {
+		return fmt.Sprint(val)
+	}
+	return val
+}
+
+// isTreeExportFormat tests if format is a machine-readable format
+// of the command tree.
+func isTreeExportFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "json", "yaml", "yml":
+		return true
+	}
+	return false
+}
+
+// printTree writes the command tree in json or yaml format.
+func printTree(wr io.Writer, tree *cmdNode, format string) (err error) {
+	var data []byte
+	switch strings.ToLower(format) {
+	case "json":
+		data, err = marshalJSON(tree, "  ")
+	case "yaml", "yml":
+		data, err = jsonToYAML(tree)
+	default:
+		err = fmt.Errorf("unknown format %q for command tree, expecting json or yaml", format)
+	}
+	if err == nil {
+		_, err = wr.Write(data)
+	}
+	return
+}
+
+// jsonToYAML encodes v as JSON, and then transcodes it to YAML
+// with the same field order.
+//
+// It is a minimal emitter for the command tree, so that cmdr
+// needs not to depend on a yaml library.
+func jsonToYAML(v any) (data []byte, err error) {
+	var js []byte
+	if js, err = marshalJSON(v, ""); err != nil {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var node any
+	if node, err = decodeOrdered(dec); err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	writeYAML(&buf, node, 0, false)
+	if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	data = buf.Bytes()
+	return
+}
+
+type yamlKV struct {
+	key string
+	val any
+}
+
+// yamlMap keeps the keys in order.
+type yamlMap []yamlKV
+
+// decodeOrdered decodes a JSON value, in which an object is
+// decoded to yamlMap to keep its keys order.
+func decodeOrdered(dec *json.Decoder) (node any, err error) {
+	var tok json.Token
+	if tok, err = dec.Token(); err != nil {
+		return
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := yamlMap{}
+			for dec.More() {
+				var kt json.Token
+				if kt, err = dec.Token(); err != nil {
+					return
+				}
+				var val any
+				if val, err = decodeOrdered(dec); err != nil {
+					return
+				}
+				m = append(m, yamlKV{key: kt.(string), val: val})
+			}
+			_, err = dec.Token() // '}'
+			node = m
+		case '[':
+			var list []any
+			for dec.More() {
+				var val any
+				if val, err = decodeOrdered(dec); err != nil {
+					return
+				}
+				list = append(list, val)
+			}
+			_, err = dec.Token() // ']'
+			node = list
+		}
+	default:
+		node = tok
+	}
+	return
+}
+
+// writeYAML writes node at the given indent level. inSeq means
+// node is an item of a sequence, so that its first line follows
+// the "- " directly.
+func writeYAML(buf *bytes.Buffer, node any, indent int, inSeq bool) {
+	pad := strings.Repeat("  ", indent)
+	switch v := node.(type) {
+	case yamlMap:
+		if len(v) == 0 {
+			buf.WriteString("{}\n")
+			return
+		}
+		for i, kv := range v {
+			if i > 0 || !inSeq {
+				buf.WriteString(pad)
+			}
+			buf.WriteString(yamlScalar(kv.key, indent))
+			buf.WriteByte(':')
+			writeYAMLValue(buf, kv.val, indent)
+		}
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]\n")
+			return
+		}
+		for i, item := range v {
+			if i > 0 || !inSeq {
+				buf.WriteString(pad)
+			}
+			buf.WriteString("- ")
+			writeYAML(buf, item, indent+1, true)
+		}
+	default:
+		buf.WriteString(yamlScalar(v, indent))
+		buf.WriteByte('\n')
+	}
+}
+
+// writeYAMLValue writes the value part of a mapping entry.
+func writeYAMLValue(buf *bytes.Buffer, val any, indent int) {
+	switch v := val.(type) {
+	case yamlMap:
+		if len(v) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, v, indent+1, false)
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, v, indent, false)
+	default:
+		buf.WriteByte(' ')
+		buf.WriteString(yamlScalar(v, indent+1))
+		buf.WriteByte('\n')
+	}
+}
+
+// yamlPlainRE matches the strings that can be written as plain
+// scalars. A leading '.' is excluded since '.5', '.inf' and '.nan'
+// are floats in YAML.
+var yamlPlainRE = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_ ./()'-]*$`)
+
+// yamlScalar formats a scalar. A multi-line string is written as
+// a literal block scalar at the given indent level.
+func yamlScalar(val any, indent int) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		switch strings.ToLower(v) {
+		case "", "true", "false", "yes", "no", "on", "off", "null", "y", "n", "~":
+			return quoteYAML(v)
+		}
+		if strings.Contains(v, "\n") && !strings.HasPrefix(v, " ") && !strings.HasSuffix(v, "\n") &&
+			!strings.Contains(v, "\r") {
+			pad := strings.Repeat("  ", indent)
+			var sb strings.Builder
+			sb.WriteString("|-")
+			for _, line := range strings.Split(v, "\n") {
+				sb.WriteByte('\n')
+				if line != "" {
+					sb.WriteString(pad)
+					sb.WriteString(line)
+				}
+			}
+			return sb.String()
+		}
+		if yamlPlainRE.MatchString(v) && !strings.HasSuffix(v, " ") {
+			return v
+		}
+		return quoteYAML(v)
+	}
+	return quoteYAML(fmt.Sprint(val))
+}
+
+// quoteYAML quotes s as a double-quoted scalar, which is
+// compatible with JSON string.
+func quoteYAML(s string) string {
+	b, _ := marshalJSON(s, "")
+	return string(bytes.TrimRight(b, "\n"))
+}
+
+// marshalJSON encodes v without escaping the markups like `<code>`.
+func marshalJSON(v any, indent string) (data []byte, err error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err = enc.Encode(v); err == nil {
+		data = buf.Bytes()
+	}
+	return
+}
+
+// showTreeExport prints the command tree of lastCmd in the
+// machine-readable format.
+func (w *workerS) showTreeExport(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, format string) (err error) {
+	tree := w.exportTree(ctx, pc, lastCmd)
+	if tree == nil {
+		return fmt.Errorf("cannot export the command tree of %v", lastCmd)
+	}
+	err = printTree((&helpPrinter{w: w}).safeGetWriter(), tree, format)
+	return
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+)
+
+func TestWorkerS_TreeExport(t *testing.T) {
+	ctx := context.Background()
+
+	var sb strings.Builder
+	app, ww := cleanApp(t, ctx, false, withHelpScreenWriter(&sb))
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "~~tree", "--json"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var tree cmdNode
+	if err := json.Unmarshal([]byte(sb.String()), &tree); err != nil {
+		t.Fatalf("bad json: %v\n%s", err, sb.String())
+	}
+	var consul *cmdNode
+	for _, c := range tree.Commands {
+		if c.Name == "consul" {
+			consul = c
+		}
+	}
+	if consul == nil || len(consul.Flags) == 0 {
+		t.Fatalf("command 'consul' not found in the exported tree: %+v", tree.Commands)
+	}
+	dc := consul.Flags[0]
+	if dc.Name != "data-center" || dc.Type != "string" || dc.Default != "dc-1" ||
+		len(dc.Shorts) == 0 || dc.Shorts[0] != "dc" || len(dc.Aliases) == 0 {
+		t.Fatalf("bad flag --data-center: %+v", dc)
+	}
+
+	sb.Reset()
+	app, ww = cleanApp(t, ctx, false, withHelpScreenWriter(&sb))
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "consul", "--help", "--format=yaml"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := "name: consul\npath: consul\nshorts:\n- c\n"; !strings.HasPrefix(sb.String(), want) {
+		t.Fatalf("expecting yaml starts with %q, but got:\n%s", want, sb.String())
+	}
+}
+
+func TestJsonToYAML(t *testing.T) {
+	data, err := jsonToYAML(map[string]any{
+		"list":  []any{map[string]any{"a": 1, "b": "x: y"}, "yes"},
+		"multi": "line 1\nline 2",
+		"empty": []string{},
+		"dots":  []any{".5", ".inf", ".NaN", "a.b", "./bin"},
+		"space": "trailing ",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `dots:
+- ".5"
+- ".inf"
+- ".NaN"
+- a.b
+- "./bin"
+empty: []
+list:
+- a: 1
+  b: "x: y"
+- "yes"
+multi: |-
+  line 1
+  line 2
+space: "trailing "
+`
+	if string(data) != expect {
+		t.Fatalf("expecting:\n%s\nbut got:\n%s", expect, data)
+	}
+}
@@ -0,0 +1,274 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hedzr/is/states"
+
+	"github.com/hedzr/cmdr/v2/cli"
+)
+
+// isTreeGraphFormat tests if format is a diagram format of the
+// command tree, for `~~tree --format=dot|mermaid|plantuml`.
+func isTreeGraphFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "dot", "graphviz", "mermaid", "plantuml", "puml":
+		return true
+	}
+	return false
+}
+
+// treeGraph is the diagram form of a command tree.
+type treeGraph struct {
+	nodes     []*graphNode
+	edges     []graphEdge
+	redirects []graphEdge
+}
+
+type graphNode struct {
+	id     string
+	label  []string // the lines of label
+	flag   bool
+	hidden bool
+}
+
+type graphEdge struct {
+	from, to string
+}
+
+// graphNodeID returns a safe and unique node id for a dotted
+// path. The bytes other than [A-Za-z0-9], '_' included, are
+// escaped as '_' and two hex digits, so 'db.migrate' and
+// 'db_migrate' get different ids, and an id never contains
+// "__", which is used by the flag nodes.
+func graphNodeID(path string) string {
+	if path == "" {
+		return "root"
+	}
+	var sb strings.Builder
+	_, _ = sb.WriteString("cmd_")
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' {
+			_ = sb.WriteByte(c)
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "_%02x", c)
+	}
+	return sb.String()
+}
+
+// buildTreeGraph builds the diagram from the command tree, the
+// redirect edges come from RedirectTo of the commands and the
+// redirect set of the root command.
+//
+// The hidden commands and flags are included in verbose mode
+// only.
+func (w *workerS) buildTreeGraph(root *cli.RootCommand, tree *cmdNode, withFlags bool) (g *treeGraph) {
+	g = &treeGraph{}
+	verbose := states.Env().CountOfVerbose() > 0
+	known := make(map[string]bool)
+
+	var walk func(node *cmdNode)
+	walk = func(node *cmdNode) {
+		id := graphNodeID(node.Path)
+		label := []string{node.Name}
+		if titles := append(append([]string{}, node.Shorts...), node.Aliases...); len(titles) > 0 {
+			label = append(label, strings.Join(titles, ", "))
+		}
+		if node.Group != "" {
+			label = append(label, "["+node.Group+"]")
+		}
+		g.nodes = append(g.nodes, &graphNode{id: id, label: label, hidden: node.Hidden || node.VendorHidden})
+		known[node.Path] = true
+
+		if withFlags {
+			for i, fn := range node.Flags {
+				if !verbose && (fn.Hidden || fn.VendorHidden) {
+					continue
+				}
+				fid := fmt.Sprintf("%s__f%d", id, i)
+				titles := []string{"--" + fn.Name}
+				for _, s := range fn.Shorts {
+					titles = append(titles, "-"+s)
+				}
+				g.nodes = append(g.nodes, &graphNode{id: fid, label: []string{strings.Join(titles, ", ")}, flag: true, hidden: fn.Hidden || fn.VendorHidden})
+				g.edges = append(g.edges, graphEdge{id, fid})
+			}
+		}
+
+		for _, child := range node.Commands {
+			if !verbose && (child.Hidden || child.VendorHidden) {
+				continue
+			}
+			g.edges = append(g.edges, graphEdge{id, graphNodeID(child.Path)})
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	seen := make(map[graphEdge]bool)
+	addRedirect := func(from, to string) {
+		if !known[from] || !known[to] {
+			return
+		}
+		e := graphEdge{graphNodeID(from), graphNodeID(to)}
+		if !seen[e] {
+			seen[e] = true
+			g.redirects = append(g.redirects, e)
+		}
+	}
+
+	var collect func(node *cmdNode)
+	collect = func(node *cmdNode) {
+		if node.RedirectTo != "" {
+			addRedirect(node.Path, node.RedirectTo)
+		}
+		for _, child := range node.Commands {
+			collect(child)
+		}
+	}
+	collect(tree)
+
+	if root != nil {
+		var targets []string
+		for target := range root.RedirectToSet() {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		for _, target := range targets {
+			for to, froms := range root.RedirectToSet()[target] {
+				for _, from := range froms {
+					addRedirect(from.GetDottedPath(), to.GetDottedPath())
+				}
+			}
+		}
+		if rt := root.RedirectTo(); rt != "" && root.Cmd != nil {
+			addRedirect(root.Cmd.GetDottedPath(), rt)
+		}
+	}
+	return
+}
+
+// printTreeGraph writes the diagram in the given format.
+func printTreeGraph(wr io.Writer, g *treeGraph, format string) (err error) {
+	var sb strings.Builder
+	switch strings.ToLower(format) {
+	case "dot", "graphviz":
+		g.writeDot(&sb)
+	case "mermaid":
+		g.writeMermaid(&sb)
+	case "plantuml", "puml":
+		g.writePlantUML(&sb)
+	default:
+		return fmt.Errorf("unknown format %q for command tree, expecting dot, mermaid or plantuml", format)
+	}
+	_, err = io.WriteString(wr, sb.String())
+	return
+}
+
+func (g *treeGraph) writeDot(sb *strings.Builder) {
+	esc := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	_, _ = sb.WriteString("digraph commands {\n  rankdir=LR;\n  node [shape=box, style=rounded];\n\n")
+	for _, n := range g.nodes {
+		var lines []string
+		for _, l := range n.label {
+			lines = append(lines, esc.Replace(l))
+		}
+		var attrs []string
+		if n.flag {
+			attrs = append(attrs, "shape=note", "fontsize=10")
+		}
+		if n.hidden {
+			attrs = append(attrs, "style=dashed", "fontcolor=gray")
+		}
+		extra := ""
+		if len(attrs) > 0 {
+			extra = ", " + strings.Join(attrs, ", ")
+		}
+		_, _ = fmt.Fprintf(sb, "  %s [label=\"%s\"%s];\n", n.id, strings.Join(lines, `\n`), extra)
+	}
+	_, _ = sb.WriteString("\n")
+	for _, e := range g.edges {
+		_, _ = fmt.Fprintf(sb, "  %s -> %s;\n", e.from, e.to)
+	}
+	for _, e := range g.redirects {
+		_, _ = fmt.Fprintf(sb, "  %s -> %s [style=dashed, label=\"redirect\"];\n", e.from, e.to)
+	}
+	_, _ = sb.WriteString("}\n")
+}
+
+func (g *treeGraph) writeMermaid(sb *strings.Builder) {
+	esc := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+	_, _ = sb.WriteString("flowchart LR\n")
+	for _, n := range g.nodes {
+		var lines []string
+		for _, l := range n.label {
+			lines = append(lines, esc.Replace(l))
+		}
+		open, closing := "[\"", "\"]"
+		if n.flag {
+			open, closing = "([\"", "\"])"
+		}
+		_, _ = fmt.Fprintf(sb, "  %s%s%s%s\n", n.id, open, strings.Join(lines, "<br/>"), closing)
+	}
+	for _, e := range g.edges {
+		_, _ = fmt.Fprintf(sb, "  %s --> %s\n", e.from, e.to)
+	}
+	for _, e := range g.redirects {
+		_, _ = fmt.Fprintf(sb, "  %s -. redirect .-> %s\n", e.from, e.to)
+	}
+	var hidden []string
+	for _, n := range g.nodes {
+		if n.hidden {
+			hidden = append(hidden, n.id)
+		}
+	}
+	if len(hidden) > 0 {
+		_, _ = sb.WriteString("  classDef hidden stroke-dasharray: 5 5, color: gray\n")
+		_, _ = fmt.Fprintf(sb, "  class %s hidden\n", strings.Join(hidden, ","))
+	}
+}
+
+func (g *treeGraph) writePlantUML(sb *strings.Builder) {
+	esc := strings.NewReplacer(`"`, "'")
+	_, _ = sb.WriteString("@startuml\nleft to right direction\n\n")
+	for _, n := range g.nodes {
+		var lines []string
+		for _, l := range n.label {
+			lines = append(lines, esc.Replace(l))
+		}
+		kind, stereotype := "rectangle", ""
+		if n.flag {
+			kind = "card"
+		}
+		if n.hidden {
+			stereotype = " <<hidden>>"
+		}
+		_, _ = fmt.Fprintf(sb, "%s \"%s\" as %s%s\n", kind, strings.Join(lines, `\n`), n.id, stereotype)
+	}
+	_, _ = sb.WriteString("\n")
+	for _, e := range g.edges {
+		_, _ = fmt.Fprintf(sb, "%s --> %s\n", e.from, e.to)
+	}
+	for _, e := range g.redirects {
+		_, _ = fmt.Fprintf(sb, "%s ..> %s : redirect\n", e.from, e.to)
+	}
+	_, _ = sb.WriteString("@enduml\n")
+}
+
+// showTreeGraph prints the command tree from lastCmd as a
+// diagram, for `~~tree --format=dot|mermaid|plantuml`.
+func (w *workerS) showTreeGraph(ctx context.Context, pc *parseCtx, lastCmd cli.Cmd, format string) (err error) {
+	tree := w.exportTree(ctx, pc, lastCmd)
+	if tree == nil {
+		return fmt.Errorf("cannot export the command tree of %v", lastCmd)
+	}
+	g := w.buildTreeGraph(lastCmd.Root(), tree, w.treeWithFlags)
+	err = printTreeGraph((&helpPrinter{w: w}).safeGetWriter(), g, format)
+	return
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/store"
+)
+
+func TestWorkerS_TreeGraph(t *testing.T) {
+	ctx := context.Background()
+
+	var sb strings.Builder
+	app, ww := cleanApp(t, ctx, false, withHelpScreenWriter(&sb))
+	ww.Config.Store = store.New()
+	ww.setArgs([]string{app.Name(), "~~tree", "--format=mermaid", "--with-flags"})
+	if err := ww.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	text := sb.String()
+	for _, want := range []string{"flowchart LR\n", "  root --> cmd_consul\n", `cmd_consul["consul<br/>c`, `(["--data-center, -dc"])`} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expecting %q in mermaid diagram, but got:\n%s", want, text)
+		}
+	}
+}
+
+func TestPrintTreeGraph(t *testing.T) {
+	tree := &cmdNode{Name: "app", Commands: []*cmdNode{
+		{Name: "server", Path: "server", Aliases: []string{"srv"}, Group: "Daemon", Flags: []flagNode{{Name: "port", Shorts: []string{"p"}}}},
+		{Name: "serve", Path: "serve", RedirectTo: "server"},
+		{Name: "secret", Path: "secret", Hidden: true},
+	}}
+	g := (&workerS{}).buildTreeGraph(nil, tree, false)
+
+	for format, expect := range map[string]string{
+		"dot": `digraph commands {
+  rankdir=LR;
+  node [shape=box, style=rounded];
+
+  root [label="app"];
+  cmd_server [label="server\nsrv\n[Daemon]"];
+  cmd_serve [label="serve"];
+
+  root -> cmd_server;
+  root -> cmd_serve;
+  cmd_serve -> cmd_server [style=dashed, label="redirect"];
+}
+`,
+		"mermaid": `flowchart LR
+  root["app"]
+  cmd_server["server<br/>srv<br/>[Daemon]"]
+  cmd_serve["serve"]
+  root --> cmd_server
+  root --> cmd_serve
+  cmd_serve -. redirect .-> cmd_server
+`,
+		"plantuml": `@startuml
+left to right direction
+
+rectangle "app" as root
+rectangle "server\nsrv\n[Daemon]" as cmd_server
+rectangle "serve" as cmd_serve
+
+root --> cmd_server
+root --> cmd_serve
+cmd_serve ..> cmd_server : redirect
+@enduml
+`,
+	} {
+		var sb strings.Builder
+		if err := printTreeGraph(&sb, g, format); err != nil {
+			t.Fatal(err)
+		}
+		if sb.String() != expect {
+			t.Fatalf("%s: expect\n%s\nbut got\n%s", format, expect, sb.String())
+		}
+	}
+
+	if err := printTreeGraph(&strings.Builder{}, g, "svg"); err == nil {
+		t.Fatal("expecting error for unknown format")
+	}
+}
+
+func TestGraphNodeID(t *testing.T) {
+	ids := make(map[string]string)
+	for _, path := range []string{"", "db", "db.migrate", "db-migrate", "db_migrate", "db_2emigrate", "db.f0", "db__f0"} {
+		id := graphNodeID(path)
+		if other, ok := ids[id]; ok {
+			t.Fatalf("expecting unique ids, but %q and %q are %q", other, path, id)
+		}
+		ids[id] = path
+	}
+	if id := graphNodeID("db.migrate"); id != "cmd_db_2emigrate" {
+		t.Fatalf("expecting cmd_db_2emigrate, got %q", id)
+	}
+}
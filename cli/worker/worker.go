@@ -9,10 +9,10 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"gopkg.in/hedzr/errors.v3"
 
-	"github.com/hedzr/is/basics"
 	"github.com/hedzr/store"
 
 	"github.com/hedzr/cmdr/v2/cli"
@@ -96,7 +96,25 @@ type workerS struct {
 	ready   int32 // rootCommand is set and ready for Run running.
 	closed  int32 // Run has exited, and all resources released
 
+	signalCode atomic.Int32 // the exit code of the received SIGINT/SIGTERM
+	phase      atomic.Int32 // the lifecycle phase, see cli.Phase
+
 	configFile      string
+	profile         string
+	locale          string
+	timeout         time.Duration
+	errorFormat     string
+	outputFormat    string
+	outputColumns   string
+	outputTemplate  string
+	noInput         bool
+	prompter        prompter
+	saveConfig      bool
+	envAll          bool
+	format          string
+	noPager         bool
+	treeWithFlags   bool
+	searchTerms     string
 	versionSimulate string
 	debugOutputFile string
 	actionsMatched  cli.ActionEnum
@@ -107,6 +125,7 @@ type workerS struct {
 	inCompleting  bool
 	actions       map[cli.ActionEnum]onAction
 	envvarMatched map[*cli.Flag]EnvVarMatched
+	dotenvs       map[string]dotenvEntry
 	parsingCtx    cli.ParsedState
 }
 
@@ -170,6 +189,12 @@ func (w *workerS) Actions() (ret map[string]bool) {
 	if e&cli.ActionShowDebug != 0 {
 		ret["show-debug"] = true
 	}
+	if e&cli.ActionShowDebugEnv != 0 {
+		ret["show-env"] = true
+	}
+	if e&cli.ActionShowHealth != 0 {
+		ret["show-health"] = true
+	}
 	if e&cli.ActionRunHelpSystem != 0 {
 		ret["run-help-system"] = true
 	}
@@ -196,7 +221,10 @@ func (w *workerS) Ready() bool {
 		cli.ActionShowHelpScreenAsMan: w.showHelpScreenAsMan,
 		cli.ActionShowTree:            w.showTree,
 		cli.ActionShowDebug:           w.showDebugScreen,
+		cli.ActionShowDebugEnv:        w.showEnvVars,
 		cli.ActionShowSBOM:            w.showSBOM,
+		cli.ActionShowSearch:          w.showSearch,
+		cli.ActionShowHealth:          w.showHealth,
 		cli.ActionRunHelpSystem:       w.runHelpSystem,
 		cli.ActionDefault:             w.onDefaultAction,
 	}
@@ -364,6 +392,16 @@ func (w *workerS) setArgs(args []string)     { w.args = args }
 func (w *workerS) Args() (args []string)     { return w.args }
 func (w *workerS) SuggestRetCode() int       { return w.retCode } //
 func (w *workerS) SetSuggestRetCode(ret int) { w.retCode = ret }
+
+// suggestRetCode suggests the exit code of the final error err
+// by [cli.ExitCodeOf] if the action didn't set one, such as
+// [cli.ExitCodeTimeout] for a [cli.TimeoutError].
+func (w *workerS) suggestRetCode(err error) {
+	if err == nil || w.retCode != 0 || w.signalCode.Load() != 0 {
+		return
+	}
+	w.retCode = cli.ExitCodeOf(err)
+}
 func (w *workerS) ParsedState() cli.ParsedState {
 	if w != nil {
 		return w.parsingCtx
@@ -435,44 +473,66 @@ func (w *workerS) Run(ctx context.Context, opts ...cli.Opt) (err error) {
 		// `app.SetCancelFunc(cancel)`
 	}
 
-	// shutdown basics.Closers for the registered Peripheral, Closers.
+	// shutdown the peripherals registered by cmdr.WithPeripherals
+	// in reverse order of opening, and basics.Closers for the registered
+	// Peripheral, Closers.
 	// See also: basics.RegisterPeripheral, basics.RegisterClosable,
 	// basics.RegisterCloseFns, basics.RegisterCloseFn, and
 	// basics.RegisterClosers
+	//
+	// An action timed out by cli.TimeoutMiddleware and ignoring its
+	// ctx may still be using them, see cli.TimeoutGrace.
 	defer func() {
-		logz.DebugContext(ctx, ".worker. closing basics.closers.")
-		basics.Close()
+		logz.DebugContext(ctx, ".worker. closing peripherals and basics.closers.")
+		w.closePeripherals(ctx)
 	}()
 
 	if w.globalCancelFunc != nil {
 		if inHelpSystem := w.Config.MustBool("cmdr.help.system.running"); !inHelpSystem {
 			defer w.globalCancelFunc()
+
+			stopSignals := w.handleSignals(ctx, w.globalCancelFunc)
+			defer func() {
+				stopSignals()
+				if code := int(w.signalCode.Load()); code != 0 && w.retCode == 0 {
+					w.retCode = code // interrupted by SIGINT or SIGTERM
+				}
+			}()
 		}
 	}
 
 	w.errs = errors.New(w.root.AppName)
 	defer w.errs.Defer(&err)
+	defer w.reportError(ctx)
+
+	pc := &parseCtx{argsPtr: &w.args, root: w.root, forceDefaultAction: w.ForceDefaultAction}
+	defer w.setPhase(ctx, cli.PhaseDone)
+	defer w.cleanup(ctx, pc) // always run TasksPostCleanup, even if preProcess failed
 
+	w.setPhase(ctx, cli.PhasePreProcess)
 	if err = w.preProcess(ctx); err != nil {
 		w.attachErrors(err)
 		return
 	}
 
-	dummy := func() bool { return true }
-	pc := &parseCtx{argsPtr: &w.args, root: w.root, forceDefaultAction: w.ForceDefaultAction}
 	defer func() { w.attachError(w.postProcess(ctx, pc)) }()
-	if w.invokeTasks(ctx, pc, w.errs, w.Config.TasksBeforeParse...) ||
-		w.attachError(w.parse(ctx, pc)) ||
-		w.invokeTasks(ctx, pc, w.errs, w.Config.TasksParsed...) ||
-		w.invokeTasks(ctx, pc, w.errs, w.Config.TasksBeforeRun...) ||
-		w.attachError(w.exec(ctx, pc)) ||
-		w.invokeTasks(ctx, pc, w.errs, w.Config.TasksAfterRun...) ||
-		w.invokeTasks(ctx, pc, w.errs, w.Config.TasksPostCleanup...) ||
-		dummy() {
-		// any errors occurred
+	if w.setPhase(ctx, cli.PhaseParse); w.invokeTasks(ctx, pc, w.errs, w.Config.TasksBeforeParse...) ||
+		w.attachError(w.parse(ctx, pc)) {
 		return
 	}
+	if w.setPhase(ctx, cli.PhaseBeforeRun); w.invokeTasks(ctx, pc, w.errs, w.Config.TasksParsed...) ||
+		w.attachError(w.openPeripherals(ctx)) ||
+		w.invokeTasks(ctx, pc, w.errs, w.Config.TasksBeforeRun...) {
+		return
+	}
+
+	w.setPhase(ctx, cli.PhaseRun)
+	w.attachError(w.exec(ctx, pc))
 
+	// TasksAfterRun are invoked once the action has been
+	// invoked, even if it failed.
+	w.setPhase(ctx, cli.PhaseAfterRun)
+	w.invokeTasks(ctx, pc, w.errs, w.Config.TasksAfterRun...)
 	return
 }
 
@@ -177,6 +177,12 @@ func ParsedState() cli.ParsedState   { return App().ParsedState() }
 // LoadedSources() is an array to represent all loaders.
 func LoadedSources() []cli.LoadedSources { return App().LoadedSources() } // the loaded config files or other sources
 
+// Profile returns the active config profile name, which was
+// selected by `--profile NAME`, `APP_PROFILE` or
+// [WithProfile]. It returns empty string if no profile
+// is active.
+func Profile() string { return App().Profile() }
+
 // Store returns the child Store tree at location 'app.cmd'.
 //
 // By default, cmdr maintains all command-line subcommands and flags
@@ -52,7 +52,7 @@ func improvedPromptMode(ctx context.Context) (err error) {
 	return
 }
 
-// func postInitTerminal(t *origterm.Terminal) {}
+// func postInitTerminal(t *xterm.Terminal) {} // xterm: golang.org/x/term
 
 func helpSystemLooper(ctx context.Context, tty term.SmallTerm, replyPrefix string, exitChan <-chan struct{}, closer func()) (err error) {
 	defer func() {
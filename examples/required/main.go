@@ -16,7 +16,7 @@ import (
 
 const (
 	appName = "required"
-	desc    = `a sample to show u what error raised by a missed required flag.`
+	desc    = `a sample to show u what error raised by a missed required flag, or the prompt for it on a terminal.`
 	version = cmdr.Version
 	author  = `The Example Authors`
 )
@@ -35,7 +35,7 @@ func main() {
 		Build()
 
 	ctx := context.Background()
-	if err := app.Run(ctx); err != nil {
+	if err := app.Run(ctx, cmdr.WithPromptRequired(true)); err != nil { // '--no-input' to disable the prompt
 		if errors.Is(err, cli.ErrRequiredFlag) {
 			fmt.Printf(color.StripLeftTabsC(`
 				The <b>REQUIRED</b> Flag not present:
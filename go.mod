@@ -18,6 +18,7 @@ require (
 	github.com/hedzr/store/codecs/json v1.4.3
 	github.com/hedzr/store/providers/file v1.4.3
 	golang.org/x/exp v0.0.0-20260611194520-c48552f49976
+	golang.org/x/term v0.45.0
 	gopkg.in/hedzr/errors.v3 v3.3.5
 )
 
@@ -25,5 +26,4 @@ require (
 	github.com/fsnotify/fsnotify v1.10.1 // indirect
 	golang.org/x/net v0.57.0 // indirect
 	golang.org/x/sys v0.47.0 // indirect
-	golang.org/x/term v0.45.0 // indirect
 )
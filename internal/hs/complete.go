@@ -0,0 +1,152 @@
+package hs
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// shellVerbs are the builtin commands of help system.
+var shellVerbs = []string{"cd", "exit", "help", "ls", "quit", "search", "set", "tree"}
+
+// completer completes the command paths and flags from the live
+// command tree, for the Tab key.
+//
+// Pressing Tab again without typing cycles through the
+// candidates.
+type completer struct {
+	s          *HelpSystem
+	last       string   // the line returned by last completion
+	candidates []string // the candidates of last completion
+	index      int
+	base       string // the line without the partial word
+}
+
+// autoComplete is a x/term.Terminal.AutoCompleteCallback.
+func (c *completer) autoComplete(line string, pos int, key rune) (newLine string, newPos int, ok bool) {
+	if key != '\t' || pos != len(line) {
+		c.candidates = nil
+		return
+	}
+
+	if c.candidates != nil && line == c.last {
+		c.index = (c.index + 1) % len(c.candidates)
+		newLine = c.base + c.candidates[c.index]
+		c.last = newLine
+		return newLine, len(newLine), true
+	}
+
+	base, candidates := c.s.complete(context.Background(), line)
+	switch len(candidates) {
+	case 0:
+		c.candidates = nil
+		return
+	case 1:
+		c.candidates = nil
+		if newLine = base + candidates[0]; !strings.HasSuffix(newLine, "=") {
+			newLine += " "
+		}
+		return newLine, len(newLine), true
+	}
+
+	partial := line[len(base):]
+	if prefix := commonPrefix(candidates); len(prefix) > len(partial) {
+		c.candidates = nil
+		newLine = base + prefix
+		return newLine, len(newLine), true
+	}
+
+	c.candidates, c.index, c.base = candidates, 0, base
+	newLine = base + candidates[0]
+	c.last = newLine
+	return newLine, len(newLine), true
+}
+
+// complete returns the candidates for the last (partial) word
+// of line, and the line without the partial word.
+//
+// The first word can be a verb or a subcommand of the current
+// command. The rest words are the subcommands, or the flags if
+// the partial word starts with '-'.
+func (s *HelpSystem) complete(ctx context.Context, line string) (base string, candidates []string) {
+	i := strings.LastIndexAny(line, " \t")
+	base, partial := line[:i+1], line[i+1:]
+	words := strings.Fields(base)
+
+	cc := s.current()
+	if len(words) > 0 {
+		switch words[0] {
+		case "set", "search", "find", "/", "quit", "exit", "q":
+			return
+		case "cd", "ls", "tree", "help", "?", "h":
+			words = words[1:]
+		}
+	}
+	for _, w := range words {
+		if strings.HasPrefix(w, "-") {
+			continue
+		}
+		if cc = s.resolveOne(ctx, cc, w); cc == nil {
+			return
+		}
+	}
+
+	if strings.HasPrefix(partial, "-") {
+		for c := cc; ; c = c.OwnerCmd() {
+			for _, ff := range c.Flags() {
+				if ff.Hidden() || ff.VendorHidden() {
+					continue
+				}
+				for _, t := range ff.GetTitleZshFlagNamesArray() {
+					if strings.HasPrefix(t, partial) {
+						candidates = append(candidates, t)
+					}
+				}
+			}
+			if c.OwnerIsNil() {
+				break
+			}
+		}
+	} else {
+		if len(words) == 0 && base == "" {
+			for _, v := range shellVerbs {
+				if strings.HasPrefix(v, partial) {
+					candidates = append(candidates, v)
+				}
+			}
+		}
+		for _, sc := range cc.SubCommands() {
+			if sc.Hidden() || sc.VendorHidden() {
+				continue
+			}
+			if n := sc.Name(); strings.HasPrefix(n, partial) {
+				candidates = append(candidates, n)
+			}
+		}
+	}
+	sort.Strings(candidates)
+	candidates = uniqStrings(candidates)
+	return
+}
+
+func commonPrefix(a []string) (prefix string) {
+	if len(a) == 0 {
+		return
+	}
+	prefix = a[0]
+	for _, s := range a[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return
+}
+
+func uniqStrings(a []string) (r []string) {
+	for i, s := range a {
+		if i == 0 || s != a[i-1] {
+			r = append(r, s)
+		}
+	}
+	return
+}
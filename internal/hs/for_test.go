@@ -1148,6 +1148,8 @@ func (*workerS) Args() []string                                         { return
 func (w *workerS) SuggestRetCode() int                                  { return w.retCode } //
 func (w *workerS) ParsedState() cli.ParsedState                         { return nil }
 func (w *workerS) LoadedSources() (results []cli.LoadedSources)         { return }
+func (w *workerS) Profile() string                                      { return "" }
+func (w *workerS) Phase() cli.Phase                                     { return cli.PhaseIdle }
 
 func (w *workerS) SetCancelFunc(cancelFunc func()) {}
 func (w *workerS) CancelFunc() func()              { return nil }
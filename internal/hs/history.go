@@ -0,0 +1,86 @@
+package hs
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxHistoryLines is the max lines kept in the history file.
+const maxHistoryLines = 1000
+
+// history keeps the lines typed in help system, and persists
+// them into a file, such as `$CACHE_DIR/help-history`.
+//
+// It implements x/term.History so the up/down arrow keys
+// can recall the lines of the previous sessions.
+type history struct {
+	file  string
+	lines []string // the oldest first
+}
+
+func newHistory(file string) *history {
+	h := &history{file: file}
+	h.load()
+	return h
+}
+
+func (h *history) load() {
+	if h.file == "" {
+		return
+	}
+	f, err := os.Open(h.file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	if n := len(h.lines); n > maxHistoryLines {
+		// shrink the history file
+		h.lines = h.lines[n-maxHistoryLines:]
+		_ = os.WriteFile(h.file, []byte(strings.Join(h.lines, "\n")+"\n"), 0o600)
+	}
+}
+
+// Add appends a line to the history and the history file.
+func (h *history) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return
+	}
+	if n := len(h.lines); n > 0 && h.lines[n-1] == entry {
+		return
+	}
+	h.lines = append(h.lines, entry)
+	if n := len(h.lines); n > maxHistoryLines {
+		h.lines = h.lines[n-maxHistoryLines:]
+	}
+	h.save(entry)
+}
+
+// Len returns the count of lines.
+func (h *history) Len() int { return len(h.lines) }
+
+// At returns a line, index 0 is the most recent one.
+func (h *history) At(idx int) string { return h.lines[len(h.lines)-1-idx] }
+
+func (h *history) save(entry string) {
+	if h.file == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.file), 0o755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(h.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.WriteString(entry + "\n")
+}
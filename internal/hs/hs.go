@@ -30,9 +30,12 @@ func New(w cli.Runner, cmd cli.Cmd, args []string) *HelpSystem {
 }
 
 type HelpSystem struct {
-	worker cli.Runner
-	cmd    cli.Cmd
-	args   []string
+	worker  cli.Runner
+	cmd     cli.Cmd
+	args    []string
+	cwd     cli.Cmd // the current command, set by `cd`
+	tty     term.SmallTerm
+	history *history
 }
 
 // for _,arg:=range args{
@@ -48,17 +51,22 @@ func (s *HelpSystem) Run(ctx context.Context) (err error) {
 
 	welcomeString := color.New().StripLeftTabsColorful(`
 	Type 'help' to print Help Screen, 'help cmd...' for a specified cmd.
+	Type 'search terms...' to search the commands, flags and help text.
+	Type 'cd cmd', 'ls' and 'tree' to navigate, Tab to complete.
+	Type 'set key value' to change a config item in Store.
 	Type 'quit' to end this session and back to Shell.
 	`).Build()
 
+	s.history = newHistory(historyFile())
+
 	var dfn2 func()
 	if dfn2, err = term.MakeNewTerm(ctx, &term.PromptModeConfig{
 		Name:              path.Join(conf.AppName, "cmdr.v2.help.system"),
 		WelcomeText:       welcomeString,
-		PromptText:        promptString,
+		PromptText:        s.prompt(),
 		ReplyText:         replyPrefix,
 		MainLooperHandler: s.helpSystemLooper,
-		// PostInitTerminal:  postInitTerminal,
+		PostInitTerminal:  s.postInitTerminal,
 	}); err != nil {
 		return
 	}
@@ -78,6 +86,7 @@ func (s *HelpSystem) helpSystemLooper(ctx context.Context, tty term.SmallTerm, r
 
 	os.Setenv("CMDR_HELP_SYS_RUNNING", "1")
 	s.cmd.Set().Set("cmdr.help.system.running", true)
+	s.tty = tty
 
 	var line string
 	for {
@@ -131,6 +140,16 @@ func (s *HelpSystem) interpretCommand(ctx context.Context, line string, term io.
 	switch a[0] {
 	case "help", "?", "h":
 		err = s.helpCmd(ctx, a[1:], term)
+	case "search", "find", "/":
+		err = s.searchCmd(ctx, a[1:], term)
+	case "cd":
+		err = s.cdCmd(ctx, a[1:], term)
+	case "ls":
+		err = s.lsCmd(ctx, a[1:], term)
+	case "tree":
+		err = s.treeCmd(ctx, a[1:], term)
+	case "set":
+		err = s.setCmd(ctx, a[1:], term)
 	default:
 		err = s.runSession(ctx, a, term)
 		if err != nil {
@@ -143,7 +162,7 @@ func (s *HelpSystem) interpretCommand(ctx context.Context, line string, term io.
 func (s *HelpSystem) helpCmd(ctx context.Context, args []string, wr io.Writer) (err error) {
 	var handled cli.Cmd
 	rootCmd := s.cmd.Root().Cmd
-	if handled, err = s.FindCmd(ctx, rootCmd, args); handled == nil {
+	if handled, err = s.resolve(ctx, args); handled == nil {
 		err = nil
 		ttl := strings.Join(args, ".")
 		cc, ff := cli.DottedPathToCommandOrFlag1(ttl, rootCmd)
@@ -181,6 +200,20 @@ Flag %v FOUND. It belongs to %v.
 	return
 }
 
+func (s *HelpSystem) searchCmd(ctx context.Context, args []string, wr io.Writer) (err error) {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(wr, "Usage: search terms...\r\n")
+		return
+	}
+	var sb strings.Builder
+	_, err = s.worker.DoBuiltinAction(ctx, cli.ActionShowSearch, s.cmd.Root().Cmd, &sb, args)
+	for line := range strings.SplitSeq(sb.String(), "\n") {
+		_, _ = wr.Write([]byte(line))
+		_, _ = wr.Write([]byte{'\r', '\n'})
+	}
+	return
+}
+
 func (s *HelpSystem) FindCmd(ctx context.Context, cmd cli.Cmd, args []string) (handled cli.Cmd, err error) {
 	// trying to recognize the given commands and print help screen of it.
 	handled = cmd.Root().Cmd
@@ -229,8 +262,14 @@ func (s *HelpSystem) runProtectedSession(ctx context.Context, a []string, term i
 	// _, _ = fmt.Fprintln(term, "Session running...", a)
 	logz.SetLevel(logz.DebugLevel)
 
+	// run the command relative to the current command
+	args := []string{os.Args[0]}
+	if cc := s.current(); !cc.OwnerIsNil() {
+		args = append(args, strings.Split(cc.GetDottedPath(), ".")...)
+	}
+
 	err = s.worker.Run(ctx,
-		cli.WithArgs(append([]string{os.Args[0]}, a...)...),
+		cli.WithArgs(append(args, a...)...),
 		cli.WithHelpScreenWriter(&crlfWriter{term}))
 	if err == nil && !s.worker.Error().IsEmpty() {
 		err = s.worker.Error()
@@ -39,3 +39,89 @@ func TestStr(t *testing.T) {
 	str = strings.ReplaceAll(str, "\n", "\r\n")
 	t.Log(str)
 }
+
+func TestHelpSystem_shell(t *testing.T) {
+	ctx := context.Background()
+	worker, root, err := rootCmdForTesting()
+	if err != nil {
+		t.Fatalf("rootCmdForTesting() failed: %v", err)
+	}
+	hs := &HelpSystem{worker: worker, cmd: root}
+
+	var sb strings.Builder
+	if err = hs.interpretCommand(ctx, "cd server", &sb); err != nil {
+		t.Fatal(err)
+	}
+	if p := hs.prompt(); p != "(cmdr server): " {
+		t.Fatalf("bad prompt %q", p)
+	}
+
+	sb.Reset()
+	_ = hs.interpretCommand(ctx, "ls", &sb)
+	t.Log(sb.String())
+	if !strings.Contains(sb.String(), "start") {
+		t.Fatalf("expecting 'start' in ls, but got:\n%s", sb.String())
+	}
+
+	_ = hs.interpretCommand(ctx, "cd ..", &sb)
+	if hs.current() != root.Cmd {
+		t.Fatalf("expecting back to root, but got %v", hs.current())
+	}
+
+	sb.Reset()
+	_ = hs.interpretCommand(ctx, "cd nothing", &sb)
+	if !strings.Contains(sb.String(), "not found") || hs.current() != root.Cmd {
+		t.Fatalf("expecting not found, but got:\n%s", sb.String())
+	}
+
+	sb.Reset()
+	_ = hs.interpretCommand(ctx, "set shell.retry 3", &sb)
+	if sb.String() != "shell.retry = 3\r\n" {
+		t.Fatalf("bad set: %q", sb.String())
+	}
+}
+
+func TestHelpSystem_complete(t *testing.T) {
+	ctx := context.Background()
+	worker, root, err := rootCmdForTesting()
+	if err != nil {
+		t.Fatalf("rootCmdForTesting() failed: %v", err)
+	}
+	hs := &HelpSystem{worker: worker, cmd: root}
+
+	for _, tc := range []struct {
+		line, base string
+		expect     string
+	}{
+		{"se", "", "search,server,set"},
+		{"help serv", "help ", "server"},
+		{"server st", "server ", "start,status,stop"},
+		{"server start --fore", "server start ", "--foreground="},
+		{"set ", "set ", ""},
+	} {
+		base, candidates := hs.complete(ctx, tc.line)
+		if base != tc.base || strings.Join(candidates, ",") != tc.expect {
+			t.Fatalf("complete(%q): expect %q, %q but got %q, %q", tc.line, tc.base, tc.expect, base, candidates)
+		}
+	}
+
+	c := &completer{s: hs}
+	line, pos, ok := c.autoComplete("help serv", 9, '\t')
+	if !ok || line != "help server " || pos != len(line) {
+		t.Fatalf("autoComplete: got %q, %v, %v", line, pos, ok)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	file := t.TempDir() + "/history"
+	h := newHistory(file)
+	h.Add("ls")
+	h.Add("cd server")
+	h.Add("cd server")
+	if h.Len() != 2 || h.At(0) != "cd server" || h.At(1) != "ls" {
+		t.Fatalf("bad history: %v", h.lines)
+	}
+	if h = newHistory(file); h.Len() != 2 || h.At(0) != "cd server" {
+		t.Fatalf("history not persisted: %v", h.lines)
+	}
+}
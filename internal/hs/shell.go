@@ -0,0 +1,219 @@
+package hs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	xterm "golang.org/x/term"
+
+	"github.com/hedzr/is/dirs"
+
+	"github.com/hedzr/cmdr/v2/cli"
+	"github.com/hedzr/cmdr/v2/conf"
+)
+
+// historyFile returns the path of the history file in app's
+// cache dir, or empty if the cache dir is unknown.
+func historyFile() string {
+	if dir := dirs.CacheDir(conf.AppName); dir != "" {
+		return filepath.Join(dir, "help-history")
+	}
+	return ""
+}
+
+// postInitTerminal hooks the tab completion and persistent
+// history into the terminal.
+func (s *HelpSystem) postInitTerminal(t *xterm.Terminal) {
+	t.AutoCompleteCallback = (&completer{s: s}).autoComplete
+	t.History = s.history
+	t.SetPrompt(s.prompt())
+}
+
+// current returns the current command set by `cd`.
+func (s *HelpSystem) current() cli.Cmd {
+	if s.cwd == nil {
+		s.cwd = s.cmd.Root().Cmd
+	}
+	return s.cwd
+}
+
+// prompt returns the prompt text for the current command.
+func (s *HelpSystem) prompt() string {
+	if cc := s.current(); !cc.OwnerIsNil() {
+		return fmt.Sprintf("(cmdr %s): ", strings.ReplaceAll(cc.GetDottedPath(), ".", " "))
+	}
+	return promptString
+}
+
+// resolveOne finds a command from cc by a word, which can be
+// `..`, `/`, or a dotted path like `server.start`.
+func (s *HelpSystem) resolveOne(ctx context.Context, cc cli.Cmd, word string) cli.Cmd {
+	if strings.HasPrefix(word, "/") {
+		cc, word = s.cmd.Root().Cmd, strings.TrimLeft(word, "/")
+	}
+	for _, part := range strings.FieldsFunc(word, func(r rune) bool { return r == '/' || r == ',' }) {
+		if part == ".." {
+			if !cc.OwnerIsNil() {
+				cc = cc.OwnerCmd()
+			}
+			continue
+		}
+		for _, name := range strings.Split(part, ".") {
+			if cc = cc.FindSubCommand(ctx, name, true); cc == nil {
+				return nil
+			}
+		}
+	}
+	return cc
+}
+
+// resolve finds a command from the current command by args.
+func (s *HelpSystem) resolve(ctx context.Context, args []string) (cc cli.Cmd, err error) {
+	cc = s.current()
+	for _, arg := range args {
+		if cc = s.resolveOne(ctx, cc, arg); cc == nil {
+			err = fmt.Errorf("%q command not found", strings.Join(args, " "))
+			return
+		}
+	}
+	return
+}
+
+// cdCmd sets the current command, `cd` without args goes back
+// to the root command.
+func (s *HelpSystem) cdCmd(ctx context.Context, args []string, wr io.Writer) (err error) {
+	cc := s.cmd.Root().Cmd
+	if len(args) > 0 {
+		if cc, err = s.resolve(ctx, args); err != nil {
+			_, _ = fmt.Fprintf(wr, "%v.\r\n", err)
+			return nil
+		}
+	}
+	s.cwd = cc
+	if s.tty != nil {
+		s.tty.SetPrompt(s.prompt())
+	}
+	return
+}
+
+// lsCmd lists the subcommands and flags of a command.
+func (s *HelpSystem) lsCmd(ctx context.Context, args []string, wr io.Writer) (err error) {
+	cc, e := s.resolve(ctx, args)
+	if e != nil {
+		_, _ = fmt.Fprintf(wr, "%v.\r\n", e)
+		return
+	}
+
+	var sb strings.Builder
+	list := func(title string, rows [][2]string) {
+		if len(rows) == 0 {
+			return
+		}
+		width := 0
+		for _, r := range rows {
+			width = max(width, len(r[0]))
+		}
+		_, _ = fmt.Fprintf(&sb, "%s:\n", title)
+		for _, r := range rows {
+			_, _ = fmt.Fprintf(&sb, "  %-*s   %s\n", width, r[0], r[1])
+		}
+	}
+
+	var cmds, flags [][2]string
+	for _, sc := range cc.SubCommands() {
+		if sc.Hidden() || sc.VendorHidden() {
+			continue
+		}
+		cmds = append(cmds, [2]string{strings.Join(sc.GetTitleNamesArray(), ", ") + "/", sc.Desc()})
+	}
+	for _, ff := range cc.Flags() {
+		if ff.Hidden() || ff.VendorHidden() {
+			continue
+		}
+		title, rest := ff.GetTitleFlagNames()
+		if rest != "" {
+			title += ", " + rest
+		}
+		flags = append(flags, [2]string{title, ff.Desc()})
+	}
+	list("Commands", cmds)
+	list("Flags", flags)
+	if len(cmds) == 0 && len(flags) == 0 {
+		_, _ = sb.WriteString("(empty)\n")
+	}
+	_, err = (&crlfWriter{wr}).WriteString(sb.String())
+	return
+}
+
+// treeCmd prints the command tree from a command.
+func (s *HelpSystem) treeCmd(ctx context.Context, args []string, wr io.Writer) (err error) {
+	cc, e := s.resolve(ctx, args)
+	if e != nil {
+		_, _ = fmt.Fprintf(wr, "%v.\r\n", e)
+		return
+	}
+	var sb strings.Builder
+	_, err = s.worker.DoBuiltinAction(ctx, cli.ActionShowTree, cc, &sb)
+	_, _ = (&crlfWriter{wr}).WriteString(sb.String())
+	return
+}
+
+// setCmd sets a value into the Store, such as `set theme.name
+// light`. `set key` prints the current value.
+//
+// The value is converted to the type of the existing one.
+func (s *HelpSystem) setCmd(ctx context.Context, args []string, wr io.Writer) (err error) {
+	_ = ctx
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(wr, "Usage: set key [value]\r\n")
+		return
+	}
+
+	st := s.cmd.Set()
+	key := args[0]
+	if len(args) > 1 {
+		old, _ := st.Get(key)
+		val, e := parseValueAs(old, strings.Join(args[1:], " "))
+		if e != nil {
+			_, _ = fmt.Fprintf(wr, "Invalid value for %q: %v.\r\n", key, e)
+			return
+		}
+		st.Set(key, val)
+	}
+	if v, ok := st.Get(key); ok {
+		_, _ = fmt.Fprintf(wr, "%s = %v\r\n", key, v)
+	} else {
+		_, _ = fmt.Fprintf(wr, "%s is not set\r\n", key)
+	}
+	return
+}
+
+// parseValueAs converts str to the type of old value.
+func parseValueAs(old any, str string) (val any, err error) {
+	switch old.(type) {
+	case bool:
+		return strconv.ParseBool(str)
+	case int:
+		return strconv.Atoi(str)
+	case int64:
+		return strconv.ParseInt(str, 0, 64)
+	case uint:
+		var v uint64
+		v, err = strconv.ParseUint(str, 0, 64)
+		return uint(v), err
+	case uint64:
+		return strconv.ParseUint(str, 0, 64)
+	case float64:
+		return strconv.ParseFloat(str, 64)
+	case time.Duration:
+		return time.ParseDuration(str)
+	case []string:
+		return strings.Split(str, ","), nil
+	}
+	return str, nil
+}
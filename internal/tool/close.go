@@ -0,0 +1,26 @@
+package tool
+
+import (
+	"time"
+)
+
+// CloseWithTimeout calls c.Close() and waits timeout at most,
+// zero means no limit. It returns false if c.Close() didn't
+// return in time, which is abandoned in background.
+func CloseWithTimeout(c interface{ Close() }, timeout time.Duration) (ok bool) {
+	if timeout <= 0 {
+		c.Close()
+		return true
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Close()
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
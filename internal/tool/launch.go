@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 
 	"github.com/hedzr/is/dir"
 	"github.com/hedzr/is/stringtool"
@@ -21,7 +22,21 @@ func LaunchEditorWithGetter(editor string, filenamegetter func() string, simulat
 	if getter == nil {
 		getter = shellEditorRandomFilename
 	}
-	return launchEditorImpl(editor, getter(), simulate)
+	return launchEditorImpl(editor, nil, getter(), simulate)
+}
+
+// LaunchEditorArgs launches the editor command-line cmdline with
+// its args, such as `code --wait`, to edit the file returned by
+// filenamegetter, a random temporary file is used if it's nil.
+func LaunchEditorArgs(cmdline []string, filenamegetter func() string) (content []byte, err error) {
+	if len(cmdline) == 0 {
+		return nil, errors.New("no editor specified")
+	}
+	getter := filenamegetter
+	if getter == nil {
+		getter = shellEditorRandomFilename
+	}
+	return launchEditorImpl(cmdline[0], cmdline[1:], getter(), false)
 }
 
 func shellEditorRandomFilename() (fn string) {
@@ -36,16 +51,16 @@ func shellEditorRandomFilename() (fn string) {
 
 // LaunchEditorWith launches the specified editor with a filename
 func LaunchEditorWith(editor, filename string) (content []byte, err error) {
-	return launchEditorImpl(editor, filename, false)
+	return launchEditorImpl(editor, nil, filename, false)
 }
 
-func launchEditorImpl(editor, filename string, simulate bool) (content []byte, err error) {
+func launchEditorImpl(editor string, args []string, filename string, simulate bool) (content []byte, err error) {
 	if simulate {
 		content = []byte(stringtool.RandomStringPure(10))
 		return
 	}
 
-	cmd := exec.Command(editor, filename)
+	cmd := exec.Command(editor, append(slices.Clone(args), filename)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -2,6 +2,8 @@ package tool
 
 import (
 	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -27,3 +29,21 @@ func TestLaunchEditorWithGetter(t *testing.T) {
 		t.Fatal("content != string(str)")
 	}
 }
+
+func TestLaunchEditorArgs(t *testing.T) {
+	if _, err := exec.LookPath("cp"); err != nil {
+		t.Skip("cp not found")
+	}
+	src, dst := filepath.Join(t.TempDir(), "src.txt"), filepath.Join(t.TempDir(), "dst.txt")
+	if err := os.WriteFile(src, []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// the editor 'cp src' copies src to the edited file
+	content, err := LaunchEditorArgs([]string{"cp", src}, func() string { return dst })
+	if err != nil || string(content) != "edited" {
+		t.Fatalf("expecting the args of editor passed, got %q, %v", content, err)
+	}
+	if _, err = LaunchEditorArgs(nil, nil); err == nil {
+		t.Fatal("expecting an error without editor")
+	}
+}
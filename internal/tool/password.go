@@ -0,0 +1,26 @@
+package tool
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// StdinIsTerminal reports whether the stdin is a terminal.
+func StdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// ReadPassword prints the prompt to stderr, and reads a line
+// from stdin without echo.
+func ReadPassword(prompt string) (text string, err error) {
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	var data []byte
+	data, err = term.ReadPassword(int(os.Stdin.Fd()))
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err == nil {
+		text = string(data)
+	}
+	return
+}
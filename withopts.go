@@ -1,7 +1,9 @@
 package cmdr
 
 import (
-	"context"
+	"io"
+	"sort"
+	"time"
 
 	"github.com/hedzr/cmdr/v2/cli"
 	"github.com/hedzr/is/basics"
@@ -134,15 +136,19 @@ func WithTasksParsed(tasks ...cli.Task) cli.Opt {
 //
 // The internal stages and user-defined tasks are:
 //   - initial
-//   - preload & xref
-//   - <tasksBeforeParse>
+//   - preload & xref                           (cli.PhasePreProcess)
+//   - <tasksBeforeParse>                       (cli.PhaseParse)
 //   - parse
-//   - <tasksParsed>
+//   - <tasksParsed>                            (cli.PhaseBeforeRun)
+//   - open the peripherals of WithPeripherals
 //   - <tasksBeforeRun> ( = tasksAfterParse )
-//   - exec (run/invoke)
-//   - <tasksAfterRun>
-//   - <tasksPostCleanup>
+//   - exec (run/invoke)                        (cli.PhaseRun)
+//   - <tasksAfterRun>                          (cli.PhaseAfterRun)
+//   - <tasksPostCleanup>                       (cli.PhaseCleanup)
 //   - basics.closers...Close()
+//
+// A task can query the current phase by runner.Phase(), see
+// [cli.Phase].
 func WithTasksBeforeRun(tasks ...cli.Task) cli.Opt {
 	return func(s *cli.Config) {
 		s.TasksBeforeRun = append(s.TasksBeforeRun, tasks...)
@@ -152,6 +158,9 @@ func WithTasksBeforeRun(tasks ...cli.Task) cli.Opt {
 // WithTasksAfterRun installs callbacks after run/invoke stage.
 //
 // The internal stages are: initial -> preload + xref -> parse -> run/invoke -> post-actions.
+//
+// The tasks are invoked once the action has been invoked, even
+// if it failed.
 func WithTasksAfterRun(tasks ...cli.Task) cli.Opt {
 	return func(s *cli.Config) {
 		s.TasksAfterRun = append(s.TasksAfterRun, tasks...)
@@ -160,6 +169,23 @@ func WithTasksAfterRun(tasks ...cli.Task) cli.Opt {
 
 // WithTasksPostCleanup install callbacks at cmdr ending.
 //
+// The cleanup tasks are always invoked, even if parsing or the
+// action failed. They receive the extras: the [cli.ParsedState],
+// the positional args and the final error (nil if ok):
+//
+//	cmdr.WithTasksPostCleanup(func(ctx context.Context, cmd cli.Cmd, runner cli.Runner, extras ...any) (err error) {
+//		if len(extras) > 2 {
+//			if e, ok := extras[2].(error); ok && e != nil {
+//				logz.Warn("app failed", "err", e)
+//			}
+//		}
+//		return db.Close()
+//	})
+//
+// A panic in a cleanup task is recovered as an error, and the
+// errors of all tasks are collected into the error returned by
+// Run.
+//
 // See the stagings order introduce at [WithTasksBeforeRun].
 //
 // See also WithTasksSetupPeripherals, WithPeripherals.
@@ -209,45 +235,220 @@ func WithTasksSetupPeripherals(tasks ...cli.Task) cli.Opt {
 //	func (o *Obj) Close(){...}                               // destroy itself
 //
 //	ctx := context.Background()                              //
-//	app := cmdr.New(cmdr.WithPeripherals(&Obj{})             // and register it to basics.Closers for auto-shutting-down
+//	app := cmdr.New(cmdr.WithPeripherals(cmdr.PeripheralMap{"obj": &Obj{}}))
 //	...
 //
 // If a peripheral implements `Open(ctx context.Context) error`, it
 // will be initialized before running a hit subcommand.
+//
+// The peripherals are registered in the order of their names.
+// They are opened in the order of their dependencies (see
+// [WithPeripheral] and cli.PeripheralDepender), or the order of
+// their names if independent, and closed in reverse order of
+// opening at exiting.
+// See also [WithOpenTimeout] and [WithCloseTimeout].
 func WithPeripherals(peripherals PeripheralMap) cli.Opt {
 	return func(s *cli.Config) {
-		s.Store.WithinLoading(func() {
-			s.Store.Set(cli.PeripheralsStoreKey, peripherals)
-		})
-		for _, peripheral := range peripherals {
-			basics.RegisterPeripheral(peripheral)
-			if p, ok := peripheral.(basics.Openable); ok {
-				WithTasksSetupPeripherals(func(ctx context.Context, cmd cli.Cmd, runner cli.Runner, extras ...any) (err error) {
-					err = p.Open(ctx)
-					return
-				})(s)
-			}
+		names := make([]string, 0, len(peripherals))
+		for name := range peripherals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			WithPeripheral(name, peripherals[name])(s)
+		}
+	}
+}
+
+// WithPeripheral registers a peripheral by name, which depends
+// on the peripherals in dependsOn:
+//
+//	app := cmdr.New(
+//		cmdr.WithPeripheral("db", db),
+//		cmdr.WithPeripheral("cache", cache, "db"), // db is opened before cache, and closed after it
+//	)
+//
+// A peripheral can also declare its dependencies by implementing
+// cli.PeripheralDepender, and report its health by implementing
+// cli.HealthChecker, which is probed by the builtin flag
+// `~~health`.
+func WithPeripheral(name string, p basics.Peripheral, dependsOn ...string) cli.Opt {
+	return func(s *cli.Config) {
+		if s.Peripherals == nil {
+			s.Peripherals = cli.NewPeripheralManager()
 		}
+		s.Peripherals.Add(name, p, dependsOn...)
+	}
+}
+
+// WithOpenTimeout sets the startup timeout of opening each
+// peripheral registered by [WithPeripherals], and the timeout of
+// probing each one by `~~health`.
+func WithOpenTimeout(d time.Duration) cli.Opt {
+	return func(s *cli.Config) {
+		s.OpenTimeout = d
+	}
+}
+
+// WithPromptRequired prompts for the values of the missing
+// required flags if the stdin is a terminal, instead of failing
+// with cli.ErrRequiredFlag. The prompt is typed by the default
+// value of the flag, lists its ValidArgs, checks its Range, reads
+// a secret flag without echo, and launches the editor for a flag
+// with ExternalEditor.
+//
+// The builtin flag '--no-input' disables the prompting.
+func WithPromptRequired(b bool) cli.Opt {
+	return func(s *cli.Config) {
+		s.PromptRequired = b
+	}
+}
+
+// WithOutputFormat enables the builtin flags '--output',
+// '--columns' and '--template', which select how the actions
+// print their results by cli.Output(ctx).Write(v).
+//
+// format is the default one: table, json, yaml or template.
+func WithOutputFormat(format string) cli.Opt {
+	return func(s *cli.Config) {
+		s.OutputFormat = format
+	}
+}
+
+// WithServer enables the builtin command group `server` to run
+// the app as a daemon, and `generate systemd` to install it.
+//
+//	err := app.Run(ctx,
+//		cmdr.WithServer(&cli.ServerOptions{
+//			Serve: func(ctx context.Context, cmd cli.Cmd, args []string) error {
+//				return serve(ctx) // returns once ctx is canceled
+//			},
+//		}),
+//	)
+//
+// The pidfile is VarRunDir()/<app>.pid, and the output of the
+// background daemon goes to VarLogDir()/<app>.log by default.
+func WithServer(o *cli.ServerOptions) cli.Opt {
+	return func(s *cli.Config) {
+		s.Server = o
 	}
 }
 
 type PeripheralMap map[string]basics.Peripheral
 
+// Peripheral returns the peripheral registered by
+// [WithPeripherals] by name, or nil if not found.
 func Peripheral(name string) basics.Peripheral {
-	var pm = Set().MustGet(cli.PeripheralsStoreKey)
-	if m, ok := pm.(map[string]basics.Peripheral); ok {
+	switch m := Set().MustGet(cli.PeripheralsStoreKey).(type) {
+	case *cli.PeripheralManager:
+		return m.Get(name)
+	case PeripheralMap:
+		return m[name]
+	case map[string]basics.Peripheral:
 		return m[name]
 	}
 	return nil
 }
 
-func PeripheralT[T basics.Peripheral](name string) T {
-	var pm = Set().MustGet(cli.PeripheralsStoreKey)
-	if m, ok := pm.(map[string]basics.Peripheral); ok {
-		return m[name].(T)
+// PeripheralT returns the peripheral registered by
+// [WithPeripherals] by name in type T, or the zero value if not
+// found or not a T.
+//
+//	db := cmdr.PeripheralT[*sql.DB]("db")
+func PeripheralT[T basics.Peripheral](name string) (t T) {
+	if p, ok := Peripheral(name).(T); ok {
+		t = p
+	}
+	return
+}
+
+// WithSignalHandling enables the graceful shutdown by signals.
+//
+// The first SIGINT or SIGTERM cancels the context passed to
+// your action, and the action has a grace period (see
+// [WithGracePeriod]) to return. A second signal, or the
+// expiration of grace period, forces exiting with the
+// conventional code 130 (SIGINT) or 143 (SIGTERM). SIGHUP is
+// routed to the reload hook if it's set, see [WithOnReload].
+//
+//	app := cmdr.New(cmdr.WithSignalHandling(true))
+//	...
+//	func serve(ctx context.Context, cmd cli.Cmd, args []string) (err error) {
+//		<-ctx.Done() // SIGINT or SIGTERM received
+//		return
+//	}
+//
+// After Run returned, app.SuggestRetCode() is 130 or 143 if the
+// app was interrupted by a signal.
+func WithSignalHandling(b bool) cli.Opt {
+	return func(s *cli.Config) {
+		s.HandleSignals = b
+	}
+}
+
+// WithGracePeriod sets how long to wait for the action after
+// the first SIGINT or SIGTERM. The default is 10s.
+func WithGracePeriod(d time.Duration) cli.Opt {
+	return func(s *cli.Config) {
+		s.GracePeriod = d
+	}
+}
+
+// WithOnReload sets the handler of SIGHUP, such as reloading
+// the config files. It works with [WithSignalHandling]. Without
+// it, SIGHUP is not handled and terminates the app as usual.
+func WithOnReload(cb cli.OnSignalHandler) cli.Opt {
+	return func(s *cli.Config) {
+		s.OnReload = cb
+	}
+}
+
+// WithCloseTimeout sets the timeout of closing each peripheral
+// registered by [WithPeripherals] at exiting. A peripheral is
+// abandoned once it timed out. Zero means no limit.
+func WithCloseTimeout(d time.Duration) cli.Opt {
+	return func(s *cli.Config) {
+		s.CloseTimeout = d
+	}
+}
+
+// WithMiddlewares registers the middlewares which wrap the
+// actions of all commands, for the cross-cutting concerns such as
+// timing, panic recovery, tracing spans, auth checks and audit
+// logging.
+//
+//	app := cmdr.New(cmdr.WithMiddlewares(
+//		cli.RecoverMiddleware(),
+//		cli.ElapsedMiddleware(),
+//	))
+//
+// The first middleware is the outermost one. The middlewares of
+// a command subtree can be added by [cli.CommandBuilder.Use].
+func WithMiddlewares(mws ...cli.Middleware) cli.Opt {
+	return func(s *cli.Config) {
+		s.Middlewares = append(s.Middlewares, mws...)
+	}
+}
+
+// WithErrorPresenter prints the error returned by Run to wr
+// (nil for os.Stderr) in format 'text' or 'json', so that main()
+// needs only exiting:
+//
+//	app := cmdr.New(cmdr.WithErrorPresenter("text", nil))
+//	if err := app.Run(ctx); err != nil {
+//		os.Exit(app.SuggestRetCode())
+//	}
+//
+// The text form is one concise line plus the hints, or the full
+// chain of errors in verbose mode. The builtin flag
+// `--error-format=json` turns it on by the end-user.
+//
+// The exit code comes from cli.ExitCodeOf, see also
+// cli.RegisterExitCode.
+func WithErrorPresenter(format string, wr io.Writer) cli.Opt {
+	return func(s *cli.Config) {
+		s.PresentErrors, s.ErrorFormat, s.ErrorWriter = true, format, wr
 	}
-	var t T
-	return t
 }
 
 func WithSortInHelpScreen(b bool) cli.Opt {
@@ -277,6 +478,17 @@ func WithDontExecuteAction(b bool) cli.Opt {
 	}
 }
 
+// WithLoadDotEnv enables loading the dotenv files.
+//
+// cmdr loads `.env`, `.env.local` and `.env.<profile>` from the
+// config dir and the working directory, before binding envvars
+// to flags. The real environment variables always win.
+func WithLoadDotEnv(b bool) cli.Opt {
+	return func(s *cli.Config) {
+		s.LoadDotEnv = b
+	}
+}
+
 // WithAutoEnvBindings enables the feature which can auto-bind env-vars
 // to flags default value.
 //
@@ -301,6 +513,127 @@ func WithAutoEnvBindings(b bool, prefix ...string) cli.Opt {
 	}
 }
 
+// WithProfile sets the default config profile.
+//
+// A profile is a subtree under "profiles.NAME" of the Store, it
+// will be overlaid onto the base Store before the flags are
+// bound. For example, with profile "prod" the entry
+// "app.profiles.prod.cmd.server.port" overrides the default
+// value of "app.cmd.server.port".
+//
+// End-user can switch to another profile by `--profile NAME`
+// or env-var `APP_PROFILE` (the prefix follows AutoEnvPrefix).
+func WithProfile(name string) cli.Opt {
+	return func(s *cli.Config) {
+		s.Profile = name
+	}
+}
+
+// WithSecretResolver registers a resolver for the indirect values
+// of secret flags.
+//
+// For example, after registered a resolver with scheme "vault",
+// the value `vault:secret/db#password` of a secret flag will be
+// resolved by it. The builtin schemes are "file" and "env", and
+// they can be overridden.
+//
+// See also [cli.FlagBuilder.Secret].
+func WithSecretResolver(scheme string, resolver cli.SecretResolver) cli.Opt {
+	return func(s *cli.Config) {
+		if s.SecretResolvers == nil {
+			s.SecretResolvers = make(map[string]cli.SecretResolver)
+		}
+		s.SecretResolvers[scheme] = resolver
+	}
+}
+
+// WithHelpPainter replaces the builtin help screen layout with
+// your own painter.
+//
+// The painter receives a [cli.HelpScreen] data model, which
+// holds the header, usage, description, grouped subcommands,
+// grouped flags, examples and footer of the requesting command.
+//
+// See also [cli.HelpPainter] and [cli.HelpPainterFunc].
+func WithHelpPainter(painter cli.HelpPainter) cli.Opt {
+	return func(s *cli.Config) {
+		s.HelpPainter = painter
+	}
+}
+
+// WithTheme sets the colors of help and debug screens.
+//
+// The builtin themes are [cli.ThemeDefault], [cli.ThemeLight]
+// (for light background terminals), [cli.ThemeHighContrast]
+// and [cli.ThemeMonochrome]. The end user can still pick
+// another one or override a role in config file, see
+// [cli.Theme].
+func WithTheme(theme *cli.Theme) cli.Opt {
+	return func(s *cli.Config) {
+		s.Theme = theme
+	}
+}
+
+// WithHelpTopics registers the non-command help pages, such as
+// `app help config-format`.
+//
+//	app := cmdr.New(cmdr.WithHelpTopics(&cli.HelpTopic{
+//		Name:        "config-format",
+//		Description: "The format of config files",
+//		Body: `
+//			# Config Files
+//
+//			The config file is a JSON file, such as
+//			` + "`$CONFIG_DIR/app.json`" + `.
+//		`,
+//	}))
+//
+// See also [cli.HelpTopic].
+func WithHelpTopics(topics ...*cli.HelpTopic) cli.Opt {
+	return func(s *cli.Config) {
+		s.HelpTopics = append(s.HelpTopics, topics...)
+	}
+}
+
+// WithLocale sets the language of help screen and messages,
+// such as "zh-CN" or "de".
+//
+// By default, the locale is detected from envvars LC_ALL,
+// LC_MESSAGES and LANG if the app has registered its own
+// translations by WithMessages, or it's English. The end user
+// can override it by `--lang LOCALE` or the config entry
+// `locale`.
+func WithLocale(locale string) cli.Opt {
+	return func(s *cli.Config) {
+		s.Locale = locale
+	}
+}
+
+// WithMessages registers the translations of a locale, for the
+// builtin labels and messages, and the descriptions of your own
+// commands and flags.
+//
+//	app := cmdr.New(cmdr.WithMessages("zh", map[string]string{
+//		"cmd.server.desc":            "服务器管理",
+//		"flg.server.start.port.desc": "监听端口",
+//		cli.MsgUsage:                 "用法",
+//	}))
+//
+// See also [cli.RegisterMessages] for the message IDs.
+func WithMessages(locale string, msgs map[string]string) cli.Opt {
+	return func(s *cli.Config) {
+		if s.Messages == nil {
+			s.Messages = make(map[string]map[string]string)
+		}
+		if s.Messages[locale] == nil {
+			s.Messages[locale] = make(map[string]string, len(msgs))
+		}
+		for k, v := range msgs {
+			s.Messages[locale][k] = v
+		}
+	}
+}
+
 // WithConfig allows you passing a [*cli.Config] object directly.
 func WithConfig(conf *cli.Config) cli.Opt {
 	return func(s *cli.Config) {